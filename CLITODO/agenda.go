@@ -0,0 +1,132 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"time"
+)
+
+const agendaDays = 7
+
+type dueItem struct {
+	Index int
+	Todo  Todo
+}
+
+// openDue ngumpulin todo yang belum selesai dan punya due, urut dari yang paling dekat
+func (todos *Todos) openDue() []dueItem {
+	var items []dueItem
+	for i, t := range *todos {
+		if t.Completed || t.Due == nil {
+			continue
+		}
+		items = append(items, dueItem{Index: i, Todo: t})
+	}
+	sort.SliceStable(items, func(a, b int) bool {
+		return items[a].Todo.Due.Before(*items[b].Todo.Due)
+	})
+	return items
+}
+
+// agenda nampilin todo yang telat plus 7 hari ke depan, dikelompokkan per hari
+func (todos *Todos) agenda(w io.Writer, cfg Config, now time.Time) {
+	r := newRenderer(cfg, false, now)
+	today := startOfDay(now)
+	end := today.AddDate(0, 0, agendaDays)
+
+	var overdue []dueItem
+	byDay := map[string][]dueItem{}
+	for _, it := range todos.openDue() {
+		due := *it.Todo.Due
+		switch {
+		case due.Before(today):
+			overdue = append(overdue, it)
+		case due.Before(end):
+			key := due.Format("2006-01-02")
+			byDay[key] = append(byDay[key], it)
+		}
+	}
+
+	if len(overdue) > 0 {
+		fmt.Fprintln(w, "Overdue")
+		for _, it := range overdue {
+			fmt.Fprintf(w, "  %-3d %s (due %s)\n", it.Index, it.Todo.Title, r.dueDate(*it.Todo.Due))
+		}
+		fmt.Fprintln(w)
+	}
+
+	for d := 0; d < agendaDays; d++ {
+		day := today.AddDate(0, 0, d)
+		header := r.day(day)
+		switch d {
+		case 0:
+			header += " (today)"
+		case 1:
+			header += " (tomorrow)"
+		}
+		fmt.Fprintln(w, header)
+
+		items := byDay[day.Format("2006-01-02")]
+		if len(items) == 0 {
+			fmt.Fprintln(w, "  -")
+			continue
+		}
+		for _, it := range items {
+			at := ""
+			if !isDateOnly(*it.Todo.Due) {
+				at = "  " + it.Todo.Due.Format("15:04")
+			}
+			fmt.Fprintf(w, "  %-3d %s%s\n", it.Index, it.Todo.Title, at)
+		}
+	}
+}
+
+// calendar nampilin grid satu bulan (mulai Senin) dengan jumlah todo
+// yang due di tiap hari, contoh: "15[2]"
+func (todos *Todos) calendar(w io.Writer, month time.Time) {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	daysInMonth := first.AddDate(0, 1, -1).Day()
+
+	counts := map[int]int{}
+	for _, it := range todos.openDue() {
+		due := *it.Todo.Due
+		if due.Year() == first.Year() && due.Month() == first.Month() {
+			counts[due.Day()]++
+		}
+	}
+
+	const cell = 7
+	title := first.Format("January 2006")
+	pad := (cell*7 - len(title)) / 2
+	fmt.Fprintf(w, "%s%s\n", strings.Repeat(" ", pad), title)
+	for _, name := range []string{"Mo", "Tu", "We", "Th", "Fr", "Sa", "Su"} {
+		fmt.Fprintf(w, "%-*s", cell, name)
+	}
+	fmt.Fprintln(w)
+
+	// Senin = kolom 0
+	col := (int(first.Weekday()) + 6) % 7
+	fmt.Fprint(w, strings.Repeat(" ", col*cell))
+	for day := 1; day <= daysInMonth; day++ {
+		mark := ""
+		switch n := counts[day]; {
+		case n > 9:
+			mark = "[9+]"
+		case n > 0:
+			mark = fmt.Sprintf("[%d]", n)
+		}
+		fmt.Fprintf(w, "%-*s", cell, fmt.Sprintf("%2d%s", day, mark))
+
+		col++
+		if col == 7 {
+			fmt.Fprintln(w)
+			col = 0
+		}
+	}
+	if col != 0 {
+		fmt.Fprintln(w)
+	}
+	fmt.Fprintln(w, "\n[n] = open todos due that day")
+}
@@ -3,9 +3,11 @@ package main
 import (
 	"flag"
 	"fmt"
+	"io"
 	"os"
 	"strconv"
 	"strings"
+	"time"
 )
 
 type cmdFlags struct {
@@ -14,15 +16,32 @@ type cmdFlags struct {
 	Edit string
 	Toggle int
 	List bool
+	Due string
+	Tags string
+	Priority string
+	Estimate string
+	After string
+	Columns string
+}
+
+// register daftarin flag lama ke fs, dipakai juga oleh shell completion
+func (cf *cmdFlags) register(fs *flag.FlagSet) {
+	fs.StringVar(&cf.Add, "add", "", "Add a new todo specify title")
+	fs.StringVar(&cf.Edit, "edit", "", "Edit a todo by index & specify a new title. id:new_title")
+	fs.IntVar(&cf.Del, "del", -1, "Specify a todo by index to delete")
+	fs.IntVar(&cf.Toggle, "Toggle", -1, "Specify a todo by index to toggle")
+	fs.BoolVar(&cf.List, "list", false, "List all todos")
+	fs.StringVar(&cf.Columns, "columns", "", "Comma separated columns to list: "+strings.Join(tableColumns, ","))
+	fs.StringVar(&cf.Tags, "tags", "", "Comma separated tags for -add or -edit")
+	fs.StringVar(&cf.Due, "due", "", "Due date for -add or -edit (YYYY-MM-DD, YYYY-MM-DD HH:MM, today, tomorrow, mon..sun, +Nd)")
+	fs.StringVar(&cf.Priority, "priority", "", "Priority for -add or -edit (none, low, medium, high)")
+	fs.StringVar(&cf.Estimate, "estimate", "", "Effort estimate for -add or -edit (minutes like 45m/1h30m, or points)")
+	fs.StringVar(&cf.After, "after", "", "Comma separated todo ids/indexes this todo depends on, for -add or -edit")
 }
 
 func NewCmdFlags() *cmdFlags {
 	cf := cmdFlags{}
-	flag.StringVar(&cf.Add, "add", "", "Add a new todo specify title")
-	flag.StringVar(&cf.Edit, "edit", "", "Edit a todo by index & specify a new title. id:new_title")
-	flag.IntVar(&cf.Del, "del", -1, "Specify a todo by index to delete")
-	flag.IntVar(&cf.Toggle, "Toggle", -1, "Specify a todo by index to toggle")
-	flag.BoolVar(&cf.List, "list", false, "List all todos")
+	cf.register(flag.CommandLine)
 
 	flag.Parse()
 
@@ -32,9 +51,10 @@ func NewCmdFlags() *cmdFlags {
 func (cf *cmdFlags) Execute (todos *Todos) {
 	switch {
 	case cf.List :
-		todos.print()
+		todos.print(cf.Columns)
 	case cf.Add != "" :
 		todos.add(cf.Add)
+		cf.applyFields(todos, len(*todos)-1)
 	case cf.Edit != "":
 		parts := strings.SplitN(cf.Edit, ":", 2)
 		if len(parts) != 2 {
@@ -48,7 +68,13 @@ func (cf *cmdFlags) Execute (todos *Todos) {
 			os.Exit(1)
 		}
 
-		todos.edit(index, parts[1])
+		// judul kosong (misal "2:") berarti cuma ubah field lain
+		if parts[1] != "" {
+			todos.edit(index, parts[1])
+		}
+		if todos.validateIndex(index) == nil {
+			cf.applyFields(todos, index)
+		}
 
 	case cf.Toggle != -1 :
 		todos.toggle(cf.Toggle)
@@ -57,4 +83,367 @@ func (cf *cmdFlags) Execute (todos *Todos) {
 		
 		default : println("Invalid Command")
 	}
-}
\ No newline at end of file
+}
+
+// applyFields ngisi field opsional (-due, -tags, -priority, -estimate, -after)
+// ke todo di index, dipakai oleh -add dan -edit
+func (cf *cmdFlags) applyFields(todos *Todos, index int) {
+	t := &(*todos)[index]
+	if cf.Due != "" {
+		t.Due = mustParseDue(cf.Due)
+	}
+	if cf.Tags != "" {
+		t.Tags = parseTags(cf.Tags)
+	}
+	if cf.Priority != "" {
+		p, err := parsePriority(cf.Priority)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		t.Priority = p
+	}
+	if cf.Estimate != "" {
+		cfg, err := loadConfig()
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		n, err := cfg.parseEstimate(cf.Estimate)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		t.Estimate = n
+	}
+	if cf.After != "" {
+		var deps []string
+		for _, ref := range parseTags(cf.After) {
+			i, err := todos.find(ref)
+			if err != nil {
+				fmt.Println("Error:", err)
+				os.Exit(1)
+			}
+			if i == index {
+				fmt.Println("Error: a todo cannot depend on itself")
+				os.Exit(1)
+			}
+			deps = append(deps, (*todos)[i].ID)
+		}
+		t.DependsOn = deps
+	}
+	t.UpdatedAt = time.Now()
+}
+
+func mustParseDue(s string) *time.Time {
+	due, err := parseDue(s, time.Now())
+	if err != nil {
+		fmt.Println("Error:", err)
+		os.Exit(1)
+	}
+	return &due
+}
+
+// subcommands yang dipanggil sebagai kata pertama, misal: todo agenda
+var subcommands = []string{"agenda", "cal", "export", "apply", "serve", "merge", "plan", "show", "edit", "focus", "completion"}
+
+func isSubcommand(name string) bool {
+	for _, s := range subcommands {
+		if s == name {
+			return true
+		}
+	}
+	return false
+}
+
+// runSubcommand jalankan subcommand, dirty true kalau todos berubah dan perlu
+// disimpan. Subcommand yang cuma baca tidak boleh nulis ulang todos.json,
+// karena mtime yang berubah bikin tool sync ngira ada konflik
+func runSubcommand(name string, args []string, todos *Todos) (dirty bool, err error) {
+	switch name {
+	case "agenda":
+		cfg, err := loadConfig()
+		if err != nil {
+			return false, err
+		}
+		todos.agenda(os.Stdout, cfg, time.Now())
+		return false, nil
+	case "cal":
+		return false, runCal(args, todos)
+	case "export":
+		return false, runExport(args, todos)
+	case "apply":
+		return runApply(args, todos)
+	case "merge":
+		return runMerge(args, todos)
+	case "plan":
+		return false, runPlan(args, todos)
+	case "show":
+		return false, runShow(args, todos)
+	}
+	return false, fmt.Errorf("unknown command %q", name)
+}
+
+func runCal(args []string, todos *Todos) error {
+	month := time.Now()
+	if len(args) > 0 {
+		m, err := time.ParseInLocation("2006-01", args[0], time.Local)
+		if err != nil {
+			return fmt.Errorf("invalid month %q, use YYYY-MM", args[0])
+		}
+		month = m
+	}
+	todos.calendar(os.Stdout, month)
+	return nil
+}
+
+func runExport(args []string, todos *Todos) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	ics := fs.Bool("ics", false, "Export todos with a due date as iCalendar")
+	all := fs.Bool("all", false, "Include completed todos")
+	out := fs.String("o", "", "Write to file instead of stdout")
+	fs.Parse(args)
+
+	if !*ics {
+		return fmt.Errorf("export needs a format, e.g. export --ics")
+	}
+
+	var w io.Writer = os.Stdout
+	if *out != "" {
+		f, err := os.Create(*out)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+	todos.exportICS(w, *all, time.Now())
+	return nil
+}
+
+func runApply(args []string, todos *Todos) (bool, error) {
+	if len(args) == 0 {
+		names, err := listTemplates()
+		if err != nil {
+			return false, err
+		}
+		if len(names) == 0 {
+			fmt.Println("No templates found in", templatesDir())
+			return false, nil
+		}
+		fmt.Println("Templates in", templatesDir())
+		for _, name := range names {
+			fmt.Println("  " + name)
+		}
+		return false, nil
+	}
+
+	tmpl, err := loadTemplate(args[0])
+	if err != nil {
+		return false, err
+	}
+
+	params := map[string]string{}
+	for _, kv := range args[1:] {
+		parts := strings.SplitN(kv, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return false, fmt.Errorf("invalid parameter %q, use key=value", kv)
+		}
+		params[parts[0]] = parts[1]
+	}
+
+	items, err := tmpl.instantiate(params, time.Now())
+	if err != nil {
+		return false, err
+	}
+	*todos = append(*todos, items...)
+	fmt.Printf("Added %d todos from template %q\n", len(items), args[0])
+	return len(items) > 0, nil
+}
+
+func loadTodosFile(name string) (Todos, error) {
+	todos := Todos{}
+	if err := NewStorage[Todos](name).Load(&todos); err != nil {
+		return nil, fmt.Errorf("%s: %w", name, err)
+	}
+	todos.ensureIDs()
+	return todos, nil
+}
+
+func runMerge(args []string, todos *Todos) (bool, error) {
+	fs := flag.NewFlagSet("merge", flag.ExitOnError)
+	out := fs.String("o", "", "Write the merged list to this file instead of todos.json")
+	dryRun := fs.Bool("dry-run", false, "Only print the report")
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "Usage: todo merge [-o file] [-dry-run] [base] ours theirs")
+		fs.PrintDefaults()
+	}
+	fs.Parse(args)
+
+	var files []Todos
+	for _, name := range fs.Args() {
+		t, err := loadTodosFile(name)
+		if err != nil {
+			return false, err
+		}
+		files = append(files, t)
+	}
+
+	var base, ours, theirs Todos
+	switch len(files) {
+	case 2:
+		ours, theirs = files[0], files[1]
+		base = guessBase(ours, theirs)
+	case 3:
+		base, ours, theirs = files[0], files[1], files[2]
+	default:
+		fs.Usage()
+		return false, fmt.Errorf("merge needs 2 or 3 files")
+	}
+
+	merged, report := mergeTodos(base, ours, theirs)
+	fmt.Println("Merge report:")
+	report.print(os.Stdout)
+
+	switch {
+	case *dryRun:
+		return false, nil
+	case *out != "":
+		return false, NewStorage[Todos](*out).Save(merged)
+	}
+	*todos = merged
+	return true, nil
+}
+
+func runPlan(args []string, todos *Todos) error {
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	capacity := fs.String("capacity", "", "Capacity per day, overrides DailyCapacity from config.json")
+	days := fs.Int("days", 1, "Number of days to show")
+	fs.Parse(args)
+
+	if *capacity != "" {
+		n, err := cfg.parseEstimate(*capacity)
+		if err != nil {
+			return err
+		}
+		if n <= 0 {
+			return fmt.Errorf("capacity must be positive")
+		}
+		cfg.DailyCapacity = n
+	}
+
+	p := todos.plan(cfg, time.Now())
+	p.print(os.Stdout, cfg, *days)
+	return nil
+}
+
+func runShow(args []string, todos *Todos) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: todo show <id>")
+	}
+	index, err := todos.find(args[0])
+	if err != nil {
+		return err
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	todos.show(os.Stdout, index, cfg)
+	return nil
+}
+
+// runEdit jalan di luar lock global karena $EDITOR bisa dibuka lama.
+// Editor dibuka dari snapshot, hasilnya baru disimpan pakai lock sendiri
+// (reload lalu cari lagi lewat ID), sama seperti focus
+func runEdit(args []string, storage *Storage[Todos]) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: todo edit <id> [--editor] [-notes text] [-link url]")
+	}
+
+	fs := flag.NewFlagSet("edit", flag.ExitOnError)
+	useEditor := fs.Bool("editor", false, "Open the todo in $EDITOR")
+	notes := fs.String("notes", "", "Replace the notes")
+	var links []string
+	fs.Func("link", "Attach a URL or file path (repeatable)", func(s string) error {
+		links = append(links, s)
+		return nil
+	})
+	fs.Parse(args[1:])
+
+	if !*useEditor && *notes == "" && len(links) == 0 {
+		return fmt.Errorf("nothing to edit, use --editor, -notes or -link")
+	}
+	cfg, err := loadConfig()
+	if err != nil {
+		return err
+	}
+	parsed, err := parseLinks(links, nil)
+	if err != nil {
+		return err
+	}
+
+	snapshot := Todos{}
+	if err := storage.Load(&snapshot); err != nil {
+		return err
+	}
+	snapshot.ensureIDs()
+	index, err := snapshot.find(args[0])
+	if err != nil {
+		return err
+	}
+	id := snapshot[index].ID
+
+	var edited Todo
+	if *useEditor {
+		edited, err = editInEditor(snapshot[index], cfg)
+		if err != nil {
+			return err
+		}
+	}
+
+	unlock, err := storage.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	todos := Todos{}
+	if err := storage.Load(&todos); err != nil {
+		return err
+	}
+	todos.ensureIDs()
+	index, err = todos.find(id)
+	if err != nil {
+		return fmt.Errorf("todo %s was removed while editing", id)
+	}
+
+	// cuma field yang bisa diedit yang ditimpa, perubahan lain yang masuk
+	// selama editor terbuka (misal sesi focus) tetap aman
+	t := &todos[index]
+	if *useEditor {
+		t.Title = edited.Title
+		t.Due = edited.Due
+		t.Tags = edited.Tags
+		t.Priority = edited.Priority
+		t.Estimate = edited.Estimate
+		t.Links = edited.Links
+		t.Notes = edited.Notes
+	} else {
+		if *notes != "" {
+			t.Notes = *notes
+		}
+		t.Links = append(t.Links, parsed...)
+	}
+	t.UpdatedAt = time.Now()
+
+	if err := storage.Save(todos); err != nil {
+		return err
+	}
+	todos.show(os.Stdout, index, cfg)
+	return nil
+}
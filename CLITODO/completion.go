@@ -0,0 +1,248 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// candidate satu saran completion, Desc ditampilkan shell yang mendukung
+// (zsh, fish) dan dipakai bash kalau sarannya lebih dari satu
+type candidate struct {
+	Value string
+	Desc  string
+}
+
+var subcommandDescs = map[string]string{
+	"agenda":     "Next 7 days grouped by day",
+	"cal":        "Month grid with due counts",
+	"export":     "Export todos (--ics)",
+	"apply":      "Add all items of a template",
+	"serve":      "Local JSON REST API",
+	"merge":      "Merge conflicting data files",
+	"plan":       "Day plan within capacity",
+	"show":       "Show one todo in detail",
+	"edit":       "Edit notes, links or everything in $EDITOR",
+	"focus":      "Pomodoro timer on one todo",
+	"completion": "Print a shell completion script",
+}
+
+// subcommandFlags flag milik tiap subcommand, harus sama dengan FlagSet-nya
+var subcommandFlags = map[string][]candidate{
+	"export": {{"-ics", "iCalendar format"}, {"-all", "Include completed todos"}, {"-o", "Write to file"}},
+	"serve":  {{"-addr", "Address to listen on"}, {"-token", "Bearer token"}},
+	"merge":  {{"-o", "Write the merged list to this file"}, {"-dry-run", "Only print the report"}},
+	"plan":   {{"-capacity", "Capacity per day"}, {"-days", "Number of days to show"}},
+	"edit":   {{"-editor", "Open in $EDITOR"}, {"-notes", "Replace the notes"}, {"-link", "Attach a URL or file path"}},
+	"focus":  {{"-work", "Length of a work session"}, {"-break", "Length of a break"}, {"-rounds", "Number of work sessions"}, {"-done", "Mark done after the last session"}},
+}
+
+// subcommand yang argumen pertamanya todo
+var takesTodo = map[string]bool{"show": true, "edit": true, "focus": true}
+
+func legacyFlags() []candidate {
+	fs := flag.NewFlagSet("todo", flag.ContinueOnError)
+	(&cmdFlags{}).register(fs)
+	var out []candidate
+	fs.VisitAll(func(f *flag.Flag) {
+		out = append(out, candidate{"-" + f.Name, f.Usage})
+	})
+	return out
+}
+
+func (todos *Todos) openTodos(byIndex bool) []candidate {
+	var out []candidate
+	for i, t := range *todos {
+		if t.Completed {
+			continue
+		}
+		value := t.ID
+		if byIndex {
+			value = strconv.Itoa(i)
+		}
+		out = append(out, candidate{value, t.Title})
+	}
+	return out
+}
+
+func (todos *Todos) allTags() []candidate {
+	count := map[string]int{}
+	for _, t := range *todos {
+		for _, tag := range t.Tags {
+			count[tag]++
+		}
+	}
+	var out []candidate
+	for tag, n := range count {
+		out = append(out, candidate{tag, fmt.Sprintf("%d todos", n)})
+	}
+	sort.Slice(out, func(a, b int) bool { return out[a].Value < out[b].Value })
+	return out
+}
+
+// withListPrefix buat nilai yang dipisah koma (-tags a,b): saran
+// dilengkapi setelah koma terakhir, nilai yang sudah diketik tidak diulang
+func withListPrefix(cur string, cands []candidate) []candidate {
+	i := strings.LastIndex(cur, ",")
+	if i < 0 {
+		return cands
+	}
+	prefix := cur[:i+1]
+	typed := map[string]bool{}
+	for _, v := range strings.Split(cur[:i], ",") {
+		typed[v] = true
+	}
+	var out []candidate
+	for _, c := range cands {
+		if !typed[c.Value] {
+			out = append(out, candidate{prefix + c.Value, c.Desc})
+		}
+	}
+	return out
+}
+
+// complete balikin saran untuk kata terakhir di words (kata yang sedang diketik)
+func (todos *Todos) complete(words []string) []candidate {
+	if len(words) == 0 {
+		words = []string{""}
+	}
+	cur := words[len(words)-1]
+	prev := ""
+	if len(words) > 1 {
+		prev = words[len(words)-2]
+	}
+	prev = "-" + strings.TrimLeft(prev, "-")
+
+	var cands []candidate
+	switch {
+	case len(words) == 1:
+		for _, name := range subcommands {
+			cands = append(cands, candidate{name, subcommandDescs[name]})
+		}
+		cands = append(cands, legacyFlags()...)
+	case prev == "-del" || prev == "-Toggle":
+		cands = todos.openTodos(true)
+	case prev == "-edit":
+		for _, c := range todos.openTodos(true) {
+			cands = append(cands, candidate{c.Value + ":", c.Desc})
+		}
+	case prev == "-after":
+		cands = withListPrefix(cur, todos.openTodos(false))
+	case prev == "-tags":
+		cands = withListPrefix(cur, todos.allTags())
+	case prev == "-columns":
+		var cols []candidate
+		for _, c := range tableColumns {
+			cols = append(cols, candidate{c, ""})
+		}
+		cands = withListPrefix(cur, cols)
+	case prev == "-priority":
+		for _, p := range priorityNames {
+			cands = append(cands, candidate{p, ""})
+		}
+	case words[0] == "completion":
+		cands = []candidate{{"bash", ""}, {"zsh", ""}, {"fish", ""}}
+	case words[0] == "apply" && len(words) == 2:
+		names, _ := listTemplates()
+		for _, name := range names {
+			cands = append(cands, candidate{name, "template"})
+		}
+	case takesTodo[words[0]] && len(words) == 2:
+		cands = todos.openTodos(false)
+	case strings.HasPrefix(cur, "-"):
+		if isSubcommand(words[0]) {
+			cands = subcommandFlags[words[0]]
+		} else {
+			cands = legacyFlags()
+		}
+	}
+
+	var out []candidate
+	for _, c := range cands {
+		if strings.HasPrefix(c.Value, cur) {
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+func printCandidates(w io.Writer, cands []candidate) {
+	for _, c := range cands {
+		if c.Desc == "" {
+			fmt.Fprintln(w, c.Value)
+			continue
+		}
+		fmt.Fprintf(w, "%s\t%s\n", c.Value, strings.ReplaceAll(c.Desc, "\n", " "))
+	}
+}
+
+const bashCompletion = `# bash completion for todo, load with: source <(todo completion bash)
+_todo_complete() {
+    local IFS=$'\n'
+    local cur="${COMP_WORDS[COMP_CWORD]}"
+    local -a lines
+    lines=($("${COMP_WORDS[0]}" __complete "${COMP_WORDS[@]:1:COMP_CWORD}" 2>/dev/null))
+    COMPREPLY=()
+    if [[ ${#lines[@]} -eq 1 ]]; then
+        COMPREPLY=("${lines[0]%%$'\t'*}")
+        return
+    fi
+    # lebih dari satu: tampilkan judul di samping nilainya
+    local line
+    for line in "${lines[@]}"; do
+        if [[ "$line" == *$'\t'* ]]; then
+            COMPREPLY+=("${line%%$'\t'*}  (${line#*$'\t'})")
+        else
+            COMPREPLY+=("$line")
+        fi
+    done
+}
+complete -o default -F _todo_complete todo
+`
+
+const zshCompletion = `#compdef todo
+# zsh completion for todo, load with: source <(todo completion zsh)
+_todo() {
+    local -a comps
+    local line value
+    for line in "${(@f)$("${words[1]}" __complete "${(@)words[2,CURRENT]}" 2>/dev/null)}"; do
+        [[ -z "$line" ]] && continue
+        value="${line%%$'\t'*}"
+        if [[ "$line" == *$'\t'* ]]; then
+            comps+=("${value//:/\\:}:${line#*$'\t'}")
+        else
+            comps+=("${value//:/\\:}")
+        fi
+    done
+    _describe -t todo 'todo' comps
+}
+compdef _todo todo
+`
+
+const fishCompletion = `# fish completion for todo, load with: todo completion fish | source
+function __todo_complete
+    set -l tokens (commandline -opc)
+    $tokens[1] __complete $tokens[2..-1] (commandline -ct) 2>/dev/null
+end
+complete -c todo -f -a '(__todo_complete)'
+`
+
+func runCompletion(args []string, w io.Writer) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: todo completion bash|zsh|fish")
+	}
+	switch args[0] {
+	case "bash":
+		fmt.Fprint(w, bashCompletion)
+	case "zsh":
+		fmt.Fprint(w, zshCompletion)
+	case "fish":
+		fmt.Fprint(w, fishCompletion)
+	default:
+		return fmt.Errorf("unsupported shell %q, use bash, zsh or fish", args[0])
+	}
+	return nil
+}
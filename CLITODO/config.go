@@ -0,0 +1,168 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// configDir balikin folder config todo, bisa dioverride pakai TODO_CONFIG_DIR
+func configDir() string {
+	if dir := os.Getenv("TODO_CONFIG_DIR"); dir != "" {
+		return dir
+	}
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ".todo"
+	}
+	return filepath.Join(dir, "todo")
+}
+
+const (
+	unitMinutes = "minutes"
+	unitPoints  = "points"
+)
+
+// Config dibaca dari <configDir>/config.json, field yang kosong pakai default
+type Config struct {
+	// EstimateUnit satuan Estimate todo dan kapasitas harian: "minutes" atau "points"
+	EstimateUnit string
+	// DailyCapacity berapa banyak kerja (dalam EstimateUnit) yang muat sehari
+	DailyCapacity int
+	// DefaultEstimate dipakai plan buat todo yang belum dikasih estimate
+	DefaultEstimate int
+
+	// Columns kolom tabel list, urut sesuai tampilan (lihat tableColumns)
+	Columns []string
+	// TimeFormat "relative" (2h ago, due in 3d) atau "absolute"
+	TimeFormat string
+	// DateFormat layout Go untuk waktu absolut
+	DateFormat string
+	// Locale bahasa nama hari/bulan dan waktu relatif: "en" atau "id"
+	Locale string
+	// Theme nama tema bawaan (default, dim, mono), Colors override per bagian
+	Theme  string
+	Colors Theme
+	// Wrap bungkus judul panjang ke baris berikutnya, default dipotong "…"
+	Wrap bool
+}
+
+func loadConfig() (Config, error) {
+	var cfg Config
+	data, err := os.ReadFile(filepath.Join(configDir(), "config.json"))
+	if err != nil && !os.IsNotExist(err) {
+		return defaultConfig(), err
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, &cfg); err != nil {
+			return defaultConfig(), fmt.Errorf("config.json: %w", err)
+		}
+	}
+	if err := cfg.applyDefaults(); err != nil {
+		return defaultConfig(), fmt.Errorf("config.json: %w", err)
+	}
+	return cfg, nil
+}
+
+// defaultConfig dipakai kalau config.json rusak, biar perintah baca-saja
+// seperti list tetap jalan
+func defaultConfig() Config {
+	var cfg Config
+	cfg.applyDefaults()
+	return cfg
+}
+
+// applyDefaults ngisi field kosong dan validasi sisanya
+func (cfg *Config) applyDefaults() error {
+	// default tergantung satuan: 6 jam sehari atau 8 points
+	switch cfg.EstimateUnit {
+	case "", unitMinutes:
+		cfg.EstimateUnit = unitMinutes
+		if cfg.DailyCapacity == 0 {
+			cfg.DailyCapacity = 6 * 60
+		}
+		if cfg.DefaultEstimate == 0 {
+			cfg.DefaultEstimate = 30
+		}
+	case unitPoints:
+		if cfg.DailyCapacity == 0 {
+			cfg.DailyCapacity = 8
+		}
+		if cfg.DefaultEstimate == 0 {
+			cfg.DefaultEstimate = 1
+		}
+	default:
+		return fmt.Errorf("EstimateUnit must be %q or %q", unitMinutes, unitPoints)
+	}
+	if cfg.DailyCapacity < 0 || cfg.DefaultEstimate < 0 {
+		return fmt.Errorf("DailyCapacity and DefaultEstimate must be positive")
+	}
+
+	if len(cfg.Columns) == 0 {
+		cfg.Columns = defaultColumns
+	}
+	if err := validateColumns(cfg.Columns); err != nil {
+		return err
+	}
+	switch cfg.TimeFormat {
+	case "":
+		cfg.TimeFormat = "relative"
+	case "relative", "absolute":
+	default:
+		return fmt.Errorf("TimeFormat must be \"relative\" or \"absolute\"")
+	}
+	if cfg.DateFormat == "" {
+		cfg.DateFormat = "Mon, 02 Jan 2006 15:04"
+	}
+	if cfg.Locale == "" {
+		cfg.Locale = "en"
+	}
+	if _, ok := locales[cfg.Locale]; !ok {
+		return fmt.Errorf("unknown Locale %q", cfg.Locale)
+	}
+	if cfg.Theme == "" {
+		cfg.Theme = "default"
+	}
+	if _, ok := themes[cfg.Theme]; !ok {
+		return fmt.Errorf("unknown Theme %q", cfg.Theme)
+	}
+	return nil
+}
+
+// parseEstimate ngurai estimate sesuai satuan: menit bisa "90", "90m"
+// atau "1h30m", points angka bulat boleh pakai akhiran "pt" seperti hasil
+// formatEstimate
+func (cfg Config) parseEstimate(s string) (int, error) {
+	s = strings.TrimSpace(s)
+	if cfg.EstimateUnit == unitPoints {
+		s = strings.TrimSpace(strings.TrimSuffix(s, "pt"))
+	}
+	if n, err := strconv.Atoi(s); err == nil && n >= 0 {
+		return n, nil
+	}
+	if cfg.EstimateUnit == unitMinutes {
+		if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+			return int(d.Minutes()), nil
+		}
+		return 0, fmt.Errorf("invalid estimate %q, use minutes like 45, 45m or 1h30m", s)
+	}
+	return 0, fmt.Errorf("invalid estimate %q, use a whole number of points like 3 or 3pt", s)
+}
+
+func (cfg Config) formatEstimate(n int) string {
+	if cfg.EstimateUnit == unitPoints {
+		return fmt.Sprintf("%dpt", n)
+	}
+	switch h, m := n/60, n%60; {
+	case h == 0:
+		return fmt.Sprintf("%dm", m)
+	case m == 0:
+		return fmt.Sprintf("%dh", h)
+	default:
+		return fmt.Sprintf("%dh%dm", h, m)
+	}
+}
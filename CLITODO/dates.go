@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// weekdays nama hari yang diterima parseDue: singkatan tiga huruf, nama
+// lengkap dan beberapa singkatan umum lain
+var weekdays = map[string]time.Weekday{
+	"sun": time.Sunday, "sunday": time.Sunday,
+	"mon": time.Monday, "monday": time.Monday,
+	"tue": time.Tuesday, "tues": time.Tuesday, "tuesday": time.Tuesday,
+	"wed": time.Wednesday, "wednesday": time.Wednesday,
+	"thu": time.Thursday, "thur": time.Thursday, "thurs": time.Thursday, "thursday": time.Thursday,
+	"fri": time.Friday, "friday": time.Friday,
+	"sat": time.Saturday, "saturday": time.Saturday,
+}
+
+func startOfDay(t time.Time) time.Time {
+	y, m, d := t.Date()
+	return time.Date(y, m, d, 0, 0, 0, 0, t.Location())
+}
+
+// isDateOnly nandain due yang cuma tanggal (jam 00:00), biar bisa
+// ditampilkan dan diexport sebagai acara seharian
+func isDateOnly(t time.Time) bool {
+	return t.Hour() == 0 && t.Minute() == 0 && t.Second() == 0
+}
+
+// parseDue nerima tanggal absolut (2006-01-02, 2006-01-02 15:04),
+// today/tomorrow, nama hari (mon..sun, hari ini atau berikutnya)
+// dan offset relatif seperti +3d atau -2d dari hari ini (+4h dari sekarang)
+func parseDue(s string, now time.Time) (time.Time, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	today := startOfDay(now)
+
+	switch s {
+	case "":
+		return time.Time{}, fmt.Errorf("empty due date")
+	case "today":
+		return today, nil
+	case "tomorrow":
+		return today.AddDate(0, 0, 1), nil
+	}
+
+	if wd, ok := weekdays[s]; ok {
+		diff := (int(wd) - int(today.Weekday()) + 7) % 7
+		return today.AddDate(0, 0, diff), nil
+	}
+
+	base := today
+	if strings.HasSuffix(s, "h") {
+		base = now
+	}
+	if t, ok := applyOffset(base, s); ok {
+		return t, nil
+	}
+
+	// s sudah lowercase, "T" di format ISO dibalikin dulu
+	abs := strings.ToUpper(s)
+	for _, layout := range []string{"2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, abs, now.Location()); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid due date %q, use YYYY-MM-DD, YYYY-MM-DD HH:MM, today, tomorrow, mon..sun, +Nd or +Nh", s)
+}
+
+// applyOffset geser base dengan offset seperti +3d, -2d, 1w atau +4h.
+// ok false kalau s memang bukan bentuk offset
+func applyOffset(base time.Time, s string) (time.Time, bool) {
+	if len(s) < 2 {
+		return time.Time{}, false
+	}
+	unit := s[len(s)-1]
+	n, err := strconv.Atoi(strings.TrimPrefix(s[:len(s)-1], "+"))
+	if err != nil {
+		return time.Time{}, false
+	}
+	switch unit {
+	case 'w':
+		return base.AddDate(0, 0, 7*n), true
+	case 'd':
+		return base.AddDate(0, 0, n), true
+	case 'h':
+		return base.Add(time.Duration(n) * time.Hour), true
+	}
+	return time.Time{}, false
+}
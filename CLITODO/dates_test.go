@@ -0,0 +1,57 @@
+package main
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDue(t *testing.T) {
+	// Rabu, 15 Mei 2024 jam 10:30
+	now := time.Date(2024, 5, 15, 10, 30, 0, 0, time.Local)
+	day := func(d int) time.Time { return time.Date(2024, 5, d, 0, 0, 0, 0, time.Local) }
+
+	cases := []struct {
+		in   string
+		want time.Time
+	}{
+		{"today", day(15)},
+		{" Tomorrow ", day(16)},
+		{"wed", day(15)},
+		{"thursday", day(16)},
+		{"mon", day(20)},
+		{"Tues", day(21)},
+		{"sunday", day(19)},
+		{"+3d", day(18)},
+		{"-2d", day(13)},
+		{"1w", day(22)},
+		{"+4h", time.Date(2024, 5, 15, 14, 30, 0, 0, time.Local)},
+		{"2024-06-01", time.Date(2024, 6, 1, 0, 0, 0, 0, time.Local)},
+		{"2024-06-01 09:15", time.Date(2024, 6, 1, 9, 15, 0, 0, time.Local)},
+		{"2024-06-01T09:15", time.Date(2024, 6, 1, 9, 15, 0, 0, time.Local)},
+	}
+	for _, c := range cases {
+		got, err := parseDue(c.in, now)
+		if err != nil {
+			t.Errorf("parseDue(%q): %v", c.in, err)
+			continue
+		}
+		if !got.Equal(c.want) {
+			t.Errorf("parseDue(%q) = %v, want %v", c.in, got, c.want)
+		}
+	}
+
+	for _, in := range []string{"", "soon", "+d", "3x", "2024-13-01", "01/06/2024", "monkey", "frisbee", "sunset", "thurday"} {
+		if _, err := parseDue(in, now); err == nil {
+			t.Errorf("parseDue(%q) should fail", in)
+		}
+	}
+}
+
+func TestIsDateOnly(t *testing.T) {
+	if !isDateOnly(time.Date(2024, 5, 15, 0, 0, 0, 0, time.Local)) {
+		t.Error("midnight should be date only")
+	}
+	if isDateOnly(time.Date(2024, 5, 15, 9, 0, 0, 0, time.Local)) {
+		t.Error("09:00 has a time")
+	}
+}
@@ -0,0 +1,256 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"time"
+)
+
+const notesMarker = "--- notes ---"
+
+// parseLinks validasi link: URL harus punya scheme dan host, selain itu
+// dianggap path file dan harus ada. "~/" diganti home directory. Link yang
+// sudah ada di existing tidak dicek lagi, jadi file lampiran yang belakangan
+// dipindah atau dihapus tidak bikin todo-nya gagal disimpan
+func parseLinks(links, existing []string) ([]string, error) {
+	var out []string
+	for _, link := range links {
+		link = strings.TrimSpace(link)
+		if link == "" {
+			continue
+		}
+		if slices.Contains(existing, link) {
+			out = append(out, link)
+			continue
+		}
+		if strings.Contains(link, "://") {
+			u, err := url.Parse(link)
+			if err != nil || u.Scheme == "" || (u.Host == "" && u.Scheme != "file") {
+				return nil, fmt.Errorf("invalid URL %q", link)
+			}
+			out = append(out, link)
+			continue
+		}
+		path := link
+		if strings.HasPrefix(path, "~/") {
+			if home, err := os.UserHomeDir(); err == nil {
+				path = filepath.Join(home, path[2:])
+			}
+		}
+		abs, err := filepath.Abs(path)
+		if err != nil {
+			return nil, err
+		}
+		if _, err := os.Stat(abs); err != nil {
+			return nil, fmt.Errorf("link %q is neither a URL nor an existing file", link)
+		}
+		out = append(out, abs)
+	}
+	return out, nil
+}
+
+// formatEditable nulis todo ke format teks yang diedit lewat $EDITOR
+func formatEditable(t Todo, cfg Config) string {
+	var b strings.Builder
+	b.WriteString("# Edit the todo below and save. Lines starting with # are ignored.\n")
+	b.WriteString("# Due: YYYY-MM-DD, YYYY-MM-DD HH:MM, today, tomorrow, mon..sun or +Nd, empty for none\n")
+	b.WriteString("# Add one Link: line per URL or file path. Empty the whole file to cancel.\n\n")
+
+	due := ""
+	if t.Due != nil {
+		if isDateOnly(*t.Due) {
+			due = t.Due.Format("2006-01-02")
+		} else {
+			due = t.Due.Format("2006-01-02 15:04")
+		}
+	}
+	estimate := ""
+	if t.Estimate > 0 {
+		estimate = cfg.formatEstimate(t.Estimate)
+	}
+
+	fmt.Fprintf(&b, "Title: %s\n", t.Title)
+	fmt.Fprintf(&b, "Due: %s\n", due)
+	fmt.Fprintf(&b, "Tags: %s\n", strings.Join(t.Tags, ", "))
+	fmt.Fprintf(&b, "Priority: %s\n", t.Priority)
+	fmt.Fprintf(&b, "Estimate: %s\n", estimate)
+	for _, link := range t.Links {
+		fmt.Fprintf(&b, "Link: %s\n", link)
+	}
+	if len(t.Links) == 0 {
+		b.WriteString("Link: \n")
+	}
+	fmt.Fprintf(&b, "\n%s\n%s", notesMarker, t.Notes)
+	if t.Notes != "" && !strings.HasSuffix(t.Notes, "\n") {
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// parseEditable baca balik hasil edit. Semua masalah dikumpulkan sekaligus
+// biar user bisa benerin semuanya dalam satu kali buka editor
+func parseEditable(text string, t Todo, cfg Config, now time.Time) (Todo, []string) {
+	var problems []string
+	var links []string
+	var notes []string
+	inNotes := false
+
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := scanner.Text()
+		if inNotes {
+			notes = append(notes, line)
+			continue
+		}
+		trimmed := strings.TrimSpace(line)
+		if trimmed == notesMarker {
+			inNotes = true
+			continue
+		}
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		key, value, ok := strings.Cut(trimmed, ":")
+		if !ok {
+			problems = append(problems, fmt.Sprintf("cannot read line %q, expected Key: value", trimmed))
+			continue
+		}
+		value = strings.TrimSpace(value)
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case "title":
+			if value == "" {
+				problems = append(problems, "Title must not be empty")
+			}
+			t.Title = value
+		case "due":
+			if value == "" {
+				t.Due = nil
+				continue
+			}
+			due, err := parseDue(value, now)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			t.Due = &due
+		case "tags":
+			t.Tags = parseTags(value)
+		case "priority":
+			if value == "" {
+				t.Priority = PriorityNone
+				continue
+			}
+			p, err := parsePriority(value)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			t.Priority = p
+		case "estimate":
+			if value == "" {
+				t.Estimate = 0
+				continue
+			}
+			n, err := cfg.parseEstimate(value)
+			if err != nil {
+				problems = append(problems, err.Error())
+				continue
+			}
+			t.Estimate = n
+		case "link":
+			links = append(links, value)
+		default:
+			problems = append(problems, fmt.Sprintf("unknown field %q", key))
+		}
+	}
+
+	parsed, err := parseLinks(links, t.Links)
+	if err != nil {
+		problems = append(problems, err.Error())
+	}
+	t.Links = parsed
+	t.Notes = strings.TrimRight(strings.Join(notes, "\n"), "\n ")
+	return t, problems
+}
+
+func editorCommand() []string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if fields := strings.Fields(os.Getenv(env)); len(fields) > 0 {
+			return fields
+		}
+	}
+	if runtime.GOOS == "windows" {
+		return []string{"notepad"}
+	}
+	return []string{"vi"}
+}
+
+var errEditCancelled = fmt.Errorf("edit cancelled, todo unchanged")
+
+// editInEditor buka todo di $EDITOR sampai isinya valid atau user batal
+func editInEditor(t Todo, cfg Config) (Todo, error) {
+	f, err := os.CreateTemp("", "todo-"+t.ID+"-*.txt")
+	if err != nil {
+		return t, err
+	}
+	name := f.Name()
+	f.Close()
+	defer os.Remove(name)
+
+	content := formatEditable(t, cfg)
+	for {
+		if err := os.WriteFile(name, []byte(content), 0600); err != nil {
+			return t, err
+		}
+
+		args := editorCommand()
+		cmd := exec.Command(args[0], append(args[1:], name)...)
+		cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := cmd.Run(); err != nil {
+			return t, fmt.Errorf("editor %s: %w", args[0], err)
+		}
+
+		data, err := os.ReadFile(name)
+		if err != nil {
+			return t, err
+		}
+		if strings.TrimSpace(string(data)) == "" {
+			return t, errEditCancelled
+		}
+
+		edited, problems := parseEditable(string(data), t, cfg, time.Now())
+		if len(problems) == 0 {
+			return edited, nil
+		}
+		for _, p := range problems {
+			fmt.Println("Error:", p)
+		}
+		// buka lagi dengan ketikan user plus daftar error di atas
+		content = withErrors(string(data), problems)
+	}
+}
+
+// withErrors taruh error terbaru di atas teks yang barusan diketik user
+// (tanpa error lama), supaya ketikan tidak hilang waktu editor dibuka lagi
+func withErrors(typed string, problems []string) string {
+	var b strings.Builder
+	for _, p := range problems {
+		fmt.Fprintf(&b, "# ERROR: %s\n", p)
+	}
+	b.WriteString("#\n")
+	for _, line := range strings.SplitAfter(typed, "\n") {
+		if strings.HasPrefix(line, "# ERROR: ") || line == "#\n" {
+			continue
+		}
+		b.WriteString(line)
+	}
+	return b.String()
+}
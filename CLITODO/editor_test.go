@@ -0,0 +1,76 @@
+package main
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestEditableRoundTrip(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.Local)
+	due := time.Date(2024, 5, 20, 14, 30, 0, 0, time.Local)
+	day := time.Date(2024, 5, 21, 0, 0, 0, 0, time.Local)
+	// file lampiran yang sudah dipindah tetap boleh disimpan ulang
+	missing := filepath.Join(t.TempDir(), "sudah-dipindah.pdf")
+
+	todos := []Todo{
+		{ID: "a", Title: "laporan", Due: &due, Tags: []string{"kerja", "q2"}, Priority: PriorityHigh, Estimate: 90,
+			Links: []string{"https://example.com/doc", missing}, Notes: "baris satu\nbaris dua"},
+		{ID: "b", Title: "tanpa apa-apa"},
+		{ID: "c", Title: "seharian", Due: &day, Estimate: 3},
+	}
+	for _, unit := range []string{unitMinutes, unitPoints} {
+		cfg := Config{EstimateUnit: unit}
+		for _, todo := range todos {
+			text := formatEditable(todo, cfg)
+			got, problems := parseEditable(text, todo, cfg, now)
+			if len(problems) > 0 {
+				t.Errorf("%s %s: unchanged save reported %v\n%s", unit, todo.ID, problems, text)
+				continue
+			}
+			if !reflect.DeepEqual(got, todo) {
+				t.Errorf("%s %s: round trip changed the todo\n got %+v\nwant %+v", unit, todo.ID, got, todo)
+			}
+		}
+	}
+}
+
+func TestParseEditableRejectsNewMissingFile(t *testing.T) {
+	todo := Todo{ID: "a", Title: "laporan"}
+	text := formatEditable(todo, Config{})
+	text = strings.Replace(text, "Link: \n", "Link: "+filepath.Join(t.TempDir(), "tidak-ada.txt")+"\n", 1)
+	if _, problems := parseEditable(text, todo, Config{}, time.Now()); len(problems) != 1 {
+		t.Fatalf("new link to a missing file should be rejected, got %v", problems)
+	}
+}
+
+func TestParseEstimate(t *testing.T) {
+	points := Config{EstimateUnit: unitPoints}
+	minutes := Config{EstimateUnit: unitMinutes}
+	cases := []struct {
+		cfg  Config
+		in   string
+		want int
+		ok   bool
+	}{
+		{points, "3", 3, true},
+		{points, "3pt", 3, true},
+		{points, "1h", 0, false},
+		{minutes, "90", 90, true},
+		{minutes, "1h30m", 90, true},
+		{minutes, "3pt", 0, false},
+	}
+	for _, c := range cases {
+		got, err := c.cfg.parseEstimate(c.in)
+		if (err == nil) != c.ok || got != c.want {
+			t.Errorf("%s parseEstimate(%q) = %d, %v", c.cfg.EstimateUnit, c.in, got, err)
+		}
+		if c.ok && got > 0 {
+			if back, err := c.cfg.parseEstimate(c.cfg.formatEstimate(got)); err != nil || back != got {
+				t.Errorf("%s: formatEstimate(%d) does not parse back: %d, %v", c.cfg.EstimateUnit, got, back, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,167 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// Session satu sesi fokus (pomodoro) yang dicatat di todo. Partial true
+// kalau sesinya dihentikan sebelum waktunya habis
+type Session struct {
+	Start   time.Time
+	End     time.Time
+	Partial bool `json:",omitempty"`
+}
+
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// focusTotal jumlah waktu fokus dan banyaknya sesi partial
+func (t Todo) focusTotal() (time.Duration, int) {
+	var total time.Duration
+	partial := 0
+	for _, s := range t.Sessions {
+		total += s.Duration()
+		if s.Partial {
+			partial++
+		}
+	}
+	return total, partial
+}
+
+const progressWidth = 24
+
+// countdown nampilin baris progress yang di-update tiap detik sampai d habis.
+// Balikin false kalau ctx dibatalkan (Ctrl-C) sebelum selesai
+func countdown(ctx context.Context, w io.Writer, label string, d time.Duration) bool {
+	start := time.Now()
+	end := start.Add(d)
+	ticker := time.NewTicker(time.Second)
+	defer ticker.Stop()
+
+	draw := func(now time.Time) {
+		left := end.Sub(now).Round(time.Second)
+		if left < 0 {
+			left = 0
+		}
+		done := int(float64(progressWidth) * float64(now.Sub(start)) / float64(d))
+		if done > progressWidth {
+			done = progressWidth
+		}
+		bar := strings.Repeat("█", done) + strings.Repeat("░", progressWidth-done)
+		fmt.Fprintf(w, "\r\x1b[K%s %s %02d:%02d left", label, bar, int(left.Minutes()), int(left.Seconds())%60)
+	}
+
+	draw(start)
+	for {
+		select {
+		case <-ctx.Done():
+			fmt.Fprintln(w)
+			return false
+		case now := <-ticker.C:
+			draw(now)
+			if !now.Before(end) {
+				fmt.Fprintln(w, "\a")
+				return true
+			}
+		}
+	}
+}
+
+// recordSession nyimpen sesi ke todo lewat alur lock -> load -> save yang
+// sama dengan CLI, jadi tidak bentrok dengan perintah lain selama fokus
+func recordSession(storage *Storage[Todos], id string, s Session, markDone bool) error {
+	unlock, err := storage.Lock()
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	todos := Todos{}
+	if err := storage.Load(&todos); err != nil {
+		return err
+	}
+	todos.ensureIDs()
+	index, err := todos.find(id)
+	if err != nil {
+		return err
+	}
+
+	t := &todos[index]
+	t.Sessions = append(t.Sessions, s)
+	if markDone && !t.Completed {
+		now := time.Now()
+		t.Completed = true
+		t.CompletedAt = &now
+	}
+	t.UpdatedAt = time.Now()
+	return storage.Save(todos)
+}
+
+func runFocus(args []string, storage *Storage[Todos]) error {
+	if len(args) == 0 || strings.HasPrefix(args[0], "-") {
+		return fmt.Errorf("usage: todo focus <id> [--work 25m] [--break 5m] [--rounds 4] [--done]")
+	}
+
+	fs := flag.NewFlagSet("focus", flag.ExitOnError)
+	work := fs.Duration("work", 25*time.Minute, "Length of a work session")
+	pause := fs.Duration("break", 5*time.Minute, "Length of a break")
+	rounds := fs.Int("rounds", 4, "Number of work sessions")
+	done := fs.Bool("done", false, "Mark the todo done after the last session")
+	fs.Parse(args[1:])
+
+	if *work <= 0 || *pause < 0 || *rounds <= 0 {
+		return fmt.Errorf("work and rounds must be positive")
+	}
+
+	// cukup baca sekali buat nyari todo-nya, lock baru diambil waktu nyimpen
+	todos := Todos{}
+	if err := storage.Load(&todos); err != nil {
+		return err
+	}
+	todos.ensureIDs()
+	index, err := todos.find(args[0])
+	if err != nil {
+		return err
+	}
+	todo := todos[index]
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Printf("Focusing on #%d %s (%d x %s, break %s). Ctrl-C to stop.\n", index, todo.Title, *rounds, *work, *pause)
+	for round := 1; round <= *rounds; round++ {
+		start := time.Now()
+		finished := countdown(ctx, os.Stdout, fmt.Sprintf("work %d/%d", round, *rounds), *work)
+		session := Session{Start: start, End: time.Now(), Partial: !finished}
+		last := round == *rounds
+		if err := recordSession(storage, todo.ID, session, finished && last && *done); err != nil {
+			return err
+		}
+		if !finished {
+			fmt.Printf("Stopped, recorded a partial session of %s\n", session.Duration().Round(time.Second))
+			return nil
+		}
+		if last {
+			break
+		}
+		if *pause > 0 && !countdown(ctx, os.Stdout, fmt.Sprintf("break %d/%d", round, *rounds-1), *pause) {
+			fmt.Println("Stopped during a break")
+			return nil
+		}
+	}
+
+	fmt.Printf("Done: %d sessions of %s on %q\n", *rounds, *work, todo.Title)
+	if *done {
+		fmt.Println("Marked as done")
+	}
+	return nil
+}
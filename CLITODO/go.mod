@@ -3,9 +3,10 @@ module todo
 go 1.25.1
 
 require (
-	github.com/aquasecurity/table v1.11.0 // indirect
-	github.com/mattn/go-runewidth v0.0.13 // indirect
-	github.com/rivo/uniseg v0.2.0 // indirect
-	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1 // indirect
-	golang.org/x/term v0.0.0-20220526004731-065cf7ba2467 // indirect
+	github.com/aquasecurity/table v1.11.0
+	github.com/mattn/go-runewidth v0.0.13
+	golang.org/x/sys v0.0.0-20210615035016-665e8c7367d1
+	golang.org/x/term v0.0.0-20220526004731-065cf7ba2467
 )
+
+require github.com/rivo/uniseg v0.2.0 // indirect
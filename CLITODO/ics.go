@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+const icsUIDDomain = "todo-cli"
+
+// icsEscape escape teks sesuai RFC 5545 (backslash, koma, titik koma, newline)
+func icsEscape(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`)
+	return r.Replace(s)
+}
+
+// icsLine nulis satu content line, dilipat tiap 75 byte sesuai RFC 5545
+func icsLine(w io.Writer, line string) {
+	const limit = 75
+	for len(line) > limit {
+		cut := limit
+		// jangan motong di tengah karakter UTF-8
+		for cut > 0 && line[cut]&0xC0 == 0x80 {
+			cut--
+		}
+		fmt.Fprintf(w, "%s\r\n", line[:cut])
+		line = " " + line[cut:]
+	}
+	fmt.Fprintf(w, "%s\r\n", line)
+}
+
+// exportICS nulis todo yang punya due sebagai VEVENT. UID diambil dari ID todo,
+// jadi kalau diimport ulang aplikasi kalender bakal update event yang sama
+func (todos *Todos) exportICS(w io.Writer, includeCompleted bool, now time.Time) {
+	stamp := now.UTC().Format("20060102T150405Z")
+
+	icsLine(w, "BEGIN:VCALENDAR")
+	icsLine(w, "VERSION:2.0")
+	icsLine(w, "PRODID:-//todo-cli//todo//EN")
+	icsLine(w, "CALSCALE:GREGORIAN")
+	for _, t := range *todos {
+		if t.Due == nil || (t.Completed && !includeCompleted) {
+			continue
+		}
+		due := *t.Due
+
+		icsLine(w, "BEGIN:VEVENT")
+		icsLine(w, fmt.Sprintf("UID:%s@%s", t.ID, icsUIDDomain))
+		icsLine(w, "DTSTAMP:"+stamp)
+		if isDateOnly(due) {
+			icsLine(w, "DTSTART;VALUE=DATE:"+due.Format("20060102"))
+			icsLine(w, "DTEND;VALUE=DATE:"+due.AddDate(0, 0, 1).Format("20060102"))
+		} else {
+			icsLine(w, "DTSTART:"+due.UTC().Format("20060102T150405Z"))
+			icsLine(w, "DTEND:"+due.Add(30*time.Minute).UTC().Format("20060102T150405Z"))
+		}
+		summary := t.Title
+		if t.Completed {
+			summary = "✔ " + summary
+		}
+		icsLine(w, "SUMMARY:"+icsEscape(summary))
+		icsLine(w, "CREATED:"+t.CreateAt.UTC().Format("20060102T150405Z"))
+		icsLine(w, "TRANSP:TRANSPARENT")
+		icsLine(w, "END:VEVENT")
+	}
+	icsLine(w, "END:VCALENDAR")
+}
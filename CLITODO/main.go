@@ -1,12 +1,83 @@
 package main
 
+import (
+	"fmt"
+	"os"
+)
+
 func main() {
 	todos := Todos{}
 	storage := NewStorage[Todos]("todos.json")
+
+	// serve jalan lama, jadi lock diambil per request bukan di sini
+	if len(os.Args) > 1 && os.Args[1] == "serve" {
+		if err := runServe(os.Args[2:], storage); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// focus juga jalan lama, sesi disimpan pakai lock sendiri tiap selesai
+	if len(os.Args) > 1 && os.Args[1] == "focus" {
+		if err := runFocus(os.Args[2:], storage); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// edit --editor bisa lama nunggu editor, lock diambil sendiri waktu simpan
+	if len(os.Args) > 1 && os.Args[1] == "edit" {
+		if err := runEdit(os.Args[2:], storage); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+
+	// completion cuma baca, tidak perlu lock dan tidak pernah save
+	if len(os.Args) > 1 && os.Args[1] == "completion" {
+		if err := runCompletion(os.Args[2:], os.Stdout); err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		return
+	}
+	if len(os.Args) > 1 && os.Args[1] == "__complete" {
+		storage.Load(&todos)
+		todos.ensureIDs()
+		printCandidates(os.Stdout, todos.complete(os.Args[2:]))
+		return
+	}
+
+	unlock, err := storage.Lock()
+	if err != nil {
+		fmt.Println("Error: cannot lock", storage.FileName, err)
+		os.Exit(1)
+	}
+	defer unlock()
+
 	storage.Load(&todos)
+	todos.ensureIDs()
+
+	if len(os.Args) > 1 && isSubcommand(os.Args[1]) {
+		dirty, err := runSubcommand(os.Args[1], os.Args[2:], &todos)
+		if err != nil {
+			fmt.Println("Error:", err)
+			os.Exit(1)
+		}
+		if dirty {
+			storage.Save(todos)
+		}
+		return
+	}
+
 	cmdFlags := NewCmdFlags()
 	cmdFlags.Execute(&todos)
-	todos.toggle(0)
-	todos.print()
-	storage.Save(todos)
+	todos.print(cmdFlags.Columns)
+	// -list cuma baca, file tidak perlu ditulis ulang
+	if !cmdFlags.List {
+		storage.Save(todos)
+	}
 }
\ No newline at end of file
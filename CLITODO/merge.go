@@ -0,0 +1,184 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"slices"
+	"time"
+)
+
+// mergeReport nyatet keputusan merge per todo, biar user bisa cek
+// apa yang diambil dari sisi mana
+type mergeReport struct {
+	lines     []string
+	conflicts int
+}
+
+func (r *mergeReport) add(t Todo, format string, args ...any) {
+	r.lines = append(r.lines, fmt.Sprintf("%s %q: %s", t.ID, t.Title, fmt.Sprintf(format, args...)))
+}
+
+func (r *mergeReport) conflict(t Todo, format string, args ...any) {
+	r.conflicts++
+	r.add(t, "CONFLICT "+format, args...)
+}
+
+func (r *mergeReport) print(w io.Writer) {
+	for _, line := range r.lines {
+		fmt.Fprintln(w, "  "+line)
+	}
+	fmt.Fprintf(w, "%d changes, %d conflicts resolved\n", len(r.lines), r.conflicts)
+}
+
+func byID(todos Todos) map[string]Todo {
+	m := make(map[string]Todo, len(todos))
+	for _, t := range todos {
+		m[t.ID] = t
+	}
+	return m
+}
+
+// guessBase nyusun ancestor kalau cuma ada dua file (misal "conflicted copy").
+// Cuma todo yang ada di dua sisi dan sama persis yang dianggap base. Todo yang
+// cuma ada di satu sisi tidak bisa dibedakan antara baru ditambah atau dihapus
+// di sisi lain, jadi selalu dianggap tambahan dan tetap disimpan. Todo yang
+// beda di dua sisi tidak punya base, jadi tiap field yang bentrok ikut sisi
+// terbaru
+func guessBase(ours, theirs Todos) Todos {
+	theirsByID := byID(theirs)
+	var base Todos
+	for _, o := range ours {
+		if t, ok := theirsByID[o.ID]; ok && sameTodo(o, t) {
+			base = append(base, o)
+		}
+	}
+	return base
+}
+
+func sameTodo(a, b Todo) bool {
+	return a.Title == b.Title && a.Completed == b.Completed &&
+		sameTime(a.CompletedAt, b.CompletedAt) && sameTime(a.Due, b.Due) &&
+		slices.Equal(a.Tags, b.Tags) && a.Priority == b.Priority &&
+		a.Estimate == b.Estimate && slices.Equal(a.DependsOn, b.DependsOn) &&
+		a.Notes == b.Notes && slices.Equal(a.Links, b.Links) &&
+		slices.EqualFunc(a.Sessions, b.Sessions, sameSession)
+}
+
+func sameSession(a, b Session) bool {
+	return a.Start.Equal(b.Start) && a.End.Equal(b.End) && a.Partial == b.Partial
+}
+
+// mergeSessions sesi fokus cuma pernah ditambah, jadi cukup digabung
+// (berdasarkan waktu mulai) tanpa konflik
+func mergeSessions(ours, theirs []Session) []Session {
+	merged := slices.Clone(ours)
+	for _, s := range theirs {
+		if !slices.ContainsFunc(merged, func(o Session) bool { return o.Start.Equal(s.Start) }) {
+			merged = append(merged, s)
+		}
+	}
+	slices.SortFunc(merged, func(a, b Session) int { return a.Start.Compare(b.Start) })
+	return merged
+}
+
+func sameTime(a, b *time.Time) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Equal(*b)
+}
+
+// pick milih nilai satu field: yang berubah dari base menang, kalau dua sisi
+// berubah beda, sisi dengan perubahan paling baru yang menang
+func pick[V any](name string, base, ours, theirs V, eq func(a, b V) bool, oursNewer bool, r *mergeReport, t Todo) V {
+	switch {
+	case eq(ours, theirs):
+		return ours
+	case eq(base, ours):
+		r.add(t, "%s taken from theirs", name)
+		return theirs
+	case eq(base, theirs):
+		return ours
+	case oursNewer:
+		r.conflict(t, "%s changed on both sides, kept ours (newer)", name)
+		return ours
+	default:
+		r.conflict(t, "%s changed on both sides, kept theirs (newer)", name)
+		return theirs
+	}
+}
+
+func mergeTodo(base, ours, theirs Todo, r *mergeReport) Todo {
+	oursNewer := !ours.lastModified().Before(theirs.lastModified())
+	eq := func(a, b string) bool { return a == b }
+
+	merged := ours
+	merged.Title = pick("Title", base.Title, ours.Title, theirs.Title, eq, oursNewer, r, ours)
+
+	type completion struct {
+		done bool
+		at   *time.Time
+	}
+	c := pick("Completed", completion{base.Completed, base.CompletedAt}, completion{ours.Completed, ours.CompletedAt}, completion{theirs.Completed, theirs.CompletedAt},
+		func(a, b completion) bool { return a.done == b.done && sameTime(a.at, b.at) }, oursNewer, r, ours)
+	merged.Completed, merged.CompletedAt = c.done, c.at
+
+	merged.Due = pick("Due", base.Due, ours.Due, theirs.Due, sameTime, oursNewer, r, ours)
+	merged.Tags = pick("Tags", base.Tags, ours.Tags, theirs.Tags, slices.Equal[[]string], oursNewer, r, ours)
+	merged.Priority = pick("Priority", base.Priority, ours.Priority, theirs.Priority, func(a, b Priority) bool { return a == b }, oursNewer, r, ours)
+	merged.Estimate = pick("Estimate", base.Estimate, ours.Estimate, theirs.Estimate, func(a, b int) bool { return a == b }, oursNewer, r, ours)
+	merged.DependsOn = pick("DependsOn", base.DependsOn, ours.DependsOn, theirs.DependsOn, slices.Equal[[]string], oursNewer, r, ours)
+	merged.Notes = pick("Notes", base.Notes, ours.Notes, theirs.Notes, eq, oursNewer, r, ours)
+	merged.Links = pick("Links", base.Links, ours.Links, theirs.Links, slices.Equal[[]string], oursNewer, r, ours)
+	merged.Sessions = mergeSessions(ours.Sessions, theirs.Sessions)
+
+	if theirs.UpdatedAt.After(merged.UpdatedAt) {
+		merged.UpdatedAt = theirs.UpdatedAt
+	}
+	return merged
+}
+
+// mergeTodos gabungin ours dan theirs berdasarkan ID dengan base sebagai
+// ancestor. Urutan ikut ours, todo baru dari theirs ditaruh di belakang
+func mergeTodos(base, ours, theirs Todos) (Todos, *mergeReport) {
+	r := &mergeReport{}
+	baseByID, oursByID, theirsByID := byID(base), byID(ours), byID(theirs)
+
+	var merged Todos
+	for _, o := range ours {
+		b, inBase := baseByID[o.ID]
+		t, inTheirs := theirsByID[o.ID]
+		switch {
+		case inTheirs && inBase:
+			merged = append(merged, mergeTodo(b, o, t, r))
+		case inTheirs:
+			// tidak ada base, field yang beda di dua sisi jadi konflik
+			merged = append(merged, mergeTodo(Todo{}, o, t, r))
+		case !inBase:
+			merged = append(merged, o)
+		case sameTodo(b, o):
+			r.add(o, "deleted in theirs")
+		default:
+			r.conflict(o, "deleted in theirs but changed in ours, kept ours")
+			merged = append(merged, o)
+		}
+	}
+
+	for _, t := range theirs {
+		if _, ok := oursByID[t.ID]; ok {
+			continue
+		}
+		b, inBase := baseByID[t.ID]
+		switch {
+		case !inBase:
+			r.add(t, "added from theirs")
+			merged = append(merged, t)
+		case sameTodo(b, t):
+			r.add(t, "deleted in ours")
+		default:
+			r.conflict(t, "deleted in ours but changed in theirs, kept theirs")
+			merged = append(merged, t)
+		}
+	}
+	return merged, r
+}
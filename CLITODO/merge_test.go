@@ -0,0 +1,126 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func mergeFixture() (time.Time, func(id, title string, updated int) Todo) {
+	start := time.Date(2024, 5, 1, 9, 0, 0, 0, time.UTC)
+	todo := func(id, title string, updated int) Todo {
+		return Todo{ID: id, Title: title, CreateAt: start, UpdatedAt: start.Add(time.Duration(updated) * time.Hour)}
+	}
+	return start, todo
+}
+
+func titles(todos Todos) []string {
+	var out []string
+	for _, t := range todos {
+		out = append(out, t.ID+":"+t.Title)
+	}
+	return out
+}
+
+func TestMergeTodos(t *testing.T) {
+	_, todo := mergeFixture()
+
+	base := Todos{todo("a", "beli susu", 0), todo("b", "bayar listrik", 0), todo("c", "rapat", 0), todo("d", "servis motor", 0)}
+
+	ours := Todos{
+		todo("a", "beli susu", 0),
+		todo("b", "bayar listrik bulan ini", 2), // diubah di ours
+		todo("c", "rapat", 0),                   // d dihapus di ours
+		todo("e", "baru dari ours", 1),
+	}
+	theirsB := todo("b", "bayar listrik", 0)
+	theirsB.Tags = []string{"rumah"} // field lain diubah di theirs
+	theirsB.UpdatedAt = theirsB.UpdatedAt.Add(time.Hour)
+	theirs := Todos{
+		todo("a", "beli susu", 0),
+		theirsB,
+		// c dihapus di theirs
+		todo("d", "servis motor", 0),
+		todo("f", "baru dari theirs", 1),
+	}
+
+	merged, report := mergeTodos(base, ours, theirs)
+	want := []string{"a:beli susu", "b:bayar listrik bulan ini", "e:baru dari ours", "f:baru dari theirs"}
+	if got := titles(merged); !slices.Equal(got, want) {
+		t.Fatalf("merged = %v, want %v", got, want)
+	}
+	if !slices.Equal(merged[1].Tags, []string{"rumah"}) {
+		t.Fatalf("tags from theirs lost: %v", merged[1].Tags)
+	}
+	if report.conflicts != 0 {
+		t.Fatalf("unexpected conflicts: %v", report.lines)
+	}
+}
+
+func TestMergeTodosConflicts(t *testing.T) {
+	_, todo := mergeFixture()
+	base := Todos{todo("a", "judul lama", 0), todo("b", "dihapus", 0)}
+
+	ours := Todos{todo("a", "judul ours", 1)}
+	theirsB := todo("b", "dihapus tapi diubah", 1)
+	theirs := Todos{todo("a", "judul theirs", 2), theirsB}
+
+	merged, report := mergeTodos(base, ours, theirs)
+	want := []string{"a:judul theirs", "b:dihapus tapi diubah"}
+	if got := titles(merged); !slices.Equal(got, want) {
+		t.Fatalf("merged = %v, want %v", got, want)
+	}
+	if report.conflicts != 2 {
+		t.Fatalf("conflicts = %d: %v", report.conflicts, report.lines)
+	}
+	if !merged[0].UpdatedAt.Equal(theirs[0].UpdatedAt) {
+		t.Fatalf("UpdatedAt should follow the newest side, got %v", merged[0].UpdatedAt)
+	}
+
+	// ours lebih baru, ours yang menang
+	ours[0].UpdatedAt = ours[0].UpdatedAt.Add(5 * time.Hour)
+	merged, _ = mergeTodos(base, ours, theirs)
+	if merged[0].Title != "judul ours" {
+		t.Fatalf("newer ours should win, got %q", merged[0].Title)
+	}
+}
+
+func TestMergeTodosSessions(t *testing.T) {
+	start, todo := mergeFixture()
+	s1 := Session{Start: start.Add(time.Hour), End: start.Add(2 * time.Hour)}
+	s2 := Session{Start: start.Add(3 * time.Hour), End: start.Add(4 * time.Hour)}
+
+	base := Todos{todo("a", "fokus", 0)}
+	ours, theirs := todo("a", "fokus", 0), todo("a", "fokus", 0)
+	ours.Sessions = []Session{s2}
+	theirs.Sessions = []Session{s1, s2}
+
+	merged, report := mergeTodos(base, Todos{ours}, Todos{theirs})
+	if len(merged) != 1 || len(merged[0].Sessions) != 2 || !merged[0].Sessions[0].Start.Equal(s1.Start) {
+		t.Fatalf("sessions = %+v", merged[0].Sessions)
+	}
+	if report.conflicts != 0 {
+		t.Fatalf("sessions should never conflict: %v", report.lines)
+	}
+}
+
+func TestGuessBase(t *testing.T) {
+	_, todo := mergeFixture()
+	// tanpa file base, todo yang cuma ada di satu sisi tidak boleh hilang
+	// walaupun lebih tua dari perubahan terakhir sisi lain
+	ours := Todos{todo("a", "sama", 0), todo("b", "lama di ours", 1), todo("c", "diubah", 2), todo("e", "baru di ours", 9)}
+	theirs := Todos{todo("a", "sama", 0), todo("c", "diubah juga", 3), todo("d", "lama di theirs", 1)}
+
+	base := guessBase(ours, theirs)
+	if got := titles(base); !slices.Equal(got, []string{"a:sama"}) {
+		t.Fatalf("base = %v", got)
+	}
+	merged, report := mergeTodos(base, ours, theirs)
+	want := []string{"a:sama", "b:lama di ours", "c:diubah juga", "e:baru di ours", "d:lama di theirs"}
+	if got := titles(merged); !slices.Equal(got, want) {
+		t.Fatalf("merged = %v, want %v", got, want)
+	}
+	if report.conflicts != 1 {
+		t.Fatalf("only c should conflict: %v", report.lines)
+	}
+}
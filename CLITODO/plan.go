@@ -0,0 +1,211 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// maxPlanDays batas simulasi, biar backlog raksasa tidak bikin loop lama
+const maxPlanDays = 90
+
+type plannedItem struct {
+	Index    int
+	Todo     Todo
+	Effort   int
+	Guessed  bool
+	Day      time.Time
+	Overflow bool
+}
+
+type dayPlan struct {
+	Day   time.Time
+	Items []plannedItem
+	Load  int
+}
+
+type planResult struct {
+	Days    []dayPlan
+	AtRisk  []plannedItem
+	Blocked []dueItem
+}
+
+// planOrder ngurutin todo yang siap dikerjakan: prioritas tinggi dulu,
+// lalu due paling dekat (yang tanpa due di belakang), lalu urutan list
+func planOrder(items []plannedItem) {
+	sort.SliceStable(items, func(a, b int) bool {
+		x, y := items[a].Todo, items[b].Todo
+		if x.Priority != y.Priority {
+			return x.Priority > y.Priority
+		}
+		switch {
+		case x.Due != nil && y.Due != nil && !x.Due.Equal(*y.Due):
+			return x.Due.Before(*y.Due)
+		case (x.Due == nil) != (y.Due == nil):
+			return x.Due != nil
+		}
+		return items[a].Index < items[b].Index
+	})
+}
+
+// plan ngisi kapasitas harian dari todo yang belum selesai, mulai hari ini.
+// Todo baru boleh masuk kalau semua dependensinya sudah selesai atau sudah
+// dijadwalkan lebih dulu. Simulasi lanjut ke hari berikutnya supaya kelihatan
+// todo mana yang bakal lewat due
+func (todos *Todos) plan(cfg Config, now time.Time) planResult {
+	open := map[string]bool{}
+	var pending []plannedItem
+	for i, t := range *todos {
+		if t.Completed {
+			continue
+		}
+		open[t.ID] = true
+		effort, guessed := t.Estimate, false
+		if effort <= 0 {
+			effort, guessed = cfg.DefaultEstimate, true
+		}
+		pending = append(pending, plannedItem{Index: i, Todo: t, Effort: effort, Guessed: guessed})
+	}
+
+	var result planResult
+	scheduled := map[string]bool{}
+	day := startOfDay(now)
+	for len(pending) > 0 && len(result.Days) < maxPlanDays {
+		dp := dayPlan{Day: day}
+		for {
+			ready := readyItems(pending, open, scheduled)
+			if len(ready) == 0 {
+				break
+			}
+			planOrder(ready)
+
+			placed := false
+			for _, it := range ready {
+				// todo yang lebih besar dari kapasitas sehari tetap dijadwalkan
+				// sendiri di hari kosong, daripada tidak pernah masuk
+				fits := dp.Load+it.Effort <= cfg.DailyCapacity
+				if !fits && !(dp.Load == 0 && it.Effort > cfg.DailyCapacity) {
+					continue
+				}
+				it.Day = day
+				it.Overflow = !fits
+				dp.Items = append(dp.Items, it)
+				dp.Load += it.Effort
+				scheduled[it.Todo.ID] = true
+				pending = removePlanned(pending, it.Todo.ID)
+				if it.Todo.Due != nil && startOfDay(*it.Todo.Due).Before(day) {
+					result.AtRisk = append(result.AtRisk, it)
+				}
+				placed = true
+				break
+			}
+			if !placed {
+				break
+			}
+		}
+		if len(dp.Items) == 0 {
+			// sisa todo saling tunggu (dependensi melingkar)
+			break
+		}
+		result.Days = append(result.Days, dp)
+		day = day.AddDate(0, 0, 1)
+	}
+
+	for _, it := range pending {
+		result.Blocked = append(result.Blocked, dueItem{Index: it.Index, Todo: it.Todo})
+		if it.Todo.Due != nil {
+			result.AtRisk = append(result.AtRisk, it)
+		}
+	}
+	return result
+}
+
+func readyItems(pending []plannedItem, open, scheduled map[string]bool) []plannedItem {
+	var ready []plannedItem
+	for _, it := range pending {
+		ok := true
+		for _, dep := range it.Todo.DependsOn {
+			if open[dep] && !scheduled[dep] {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			ready = append(ready, it)
+		}
+	}
+	return ready
+}
+
+func removePlanned(items []plannedItem, id string) []plannedItem {
+	for i, it := range items {
+		if it.Todo.ID == id {
+			return append(items[:i], items[i+1:]...)
+		}
+	}
+	return items
+}
+
+func (p planResult) print(w io.Writer, cfg Config, days int) {
+	if len(p.Days) == 0 && len(p.Blocked) == 0 {
+		fmt.Fprintln(w, "Nothing to plan, all todos are done")
+		return
+	}
+
+	r := newRenderer(cfg, false, time.Now())
+	guessed := false
+	for i, dp := range p.Days {
+		if i >= days {
+			break
+		}
+		fmt.Fprintf(w, "Plan for %s (%s of %s)\n", r.day(dp.Day),
+			cfg.formatEstimate(dp.Load), cfg.formatEstimate(cfg.DailyCapacity))
+		for _, it := range dp.Items {
+			effort := cfg.formatEstimate(it.Effort)
+			if it.Guessed {
+				effort += "?"
+				guessed = true
+			}
+			extra := ""
+			if it.Todo.Priority != PriorityNone {
+				extra += " [" + it.Todo.Priority.String() + "]"
+			}
+			if it.Todo.Due != nil {
+				extra += " due " + r.dueDate(*it.Todo.Due)
+			}
+			if it.Overflow {
+				extra += " (larger than a day)"
+			}
+			fmt.Fprintf(w, "  %-3d %-7s %s%s\n", it.Index, effort, it.Todo.Title, extra)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(p.Days) > days {
+		last := p.Days[len(p.Days)-1].Day
+		fmt.Fprintf(w, "Remaining open todos need %d more day(s), until %s\n\n", len(p.Days)-days, r.day(last))
+	}
+
+	if len(p.AtRisk) > 0 {
+		fmt.Fprintln(w, "At risk of missing their due date")
+		for _, it := range p.AtRisk {
+			when := "not schedulable"
+			if !it.Day.IsZero() {
+				when = "planned " + r.day(it.Day)
+			}
+			fmt.Fprintf(w, "  %-3d %s (due %s, %s)\n", it.Index, it.Todo.Title, r.dueDate(*it.Todo.Due), when)
+		}
+		fmt.Fprintln(w)
+	}
+
+	if len(p.Blocked) > 0 {
+		fmt.Fprintf(w, "Not planned (dependency cycle or beyond %d days)\n", maxPlanDays)
+		for _, it := range p.Blocked {
+			fmt.Fprintf(w, "  %-3d %s\n", it.Index, it.Todo.Title)
+		}
+	}
+	if guessed {
+		fmt.Fprintln(w, "? = no estimate, default used")
+	}
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func planIDs(items []plannedItem) []string {
+	var ids []string
+	for _, it := range items {
+		ids = append(ids, it.Todo.ID)
+	}
+	return ids
+}
+
+func TestPlan(t *testing.T) {
+	now := time.Date(2024, 5, 15, 10, 0, 0, 0, time.Local)
+	today := startOfDay(now)
+	due := today
+	cfg := Config{DailyCapacity: 60, DefaultEstimate: 30}
+
+	todos := Todos{
+		{ID: "a", Title: "sudah beres", Completed: true, Estimate: 10},
+		{ID: "b", Title: "bayar listrik", Priority: PriorityLow, Estimate: 30, Due: &due},
+		{ID: "c", Title: "laporan", Priority: PriorityHigh, Estimate: 40},
+		{ID: "d", Title: "kirim laporan", DependsOn: []string{"c"}},
+		{ID: "e", Title: "pindahan", Estimate: 120},
+		{ID: "f", Title: "nunggu g", DependsOn: []string{"g"}, Due: &due},
+		{ID: "g", Title: "nunggu f", DependsOn: []string{"f"}},
+	}
+	result := todos.plan(cfg, now)
+
+	want := [][]string{{"c"}, {"b", "d"}, {"e"}}
+	if len(result.Days) != len(want) {
+		t.Fatalf("planned %d days, want %d", len(result.Days), len(want))
+	}
+	for i, dp := range result.Days {
+		if !dp.Day.Equal(today.AddDate(0, 0, i)) {
+			t.Errorf("day %d = %v", i, dp.Day)
+		}
+		if got := planIDs(dp.Items); !slices.Equal(got, want[i]) {
+			t.Errorf("day %d = %v, want %v", i, got, want[i])
+		}
+	}
+	if result.Days[1].Load != 60 {
+		t.Errorf("day 1 load = %d", result.Days[1].Load)
+	}
+
+	d := result.Days[1].Items[1]
+	if !d.Guessed || d.Effort != cfg.DefaultEstimate {
+		t.Errorf("todo without estimate should use the default: %+v", d)
+	}
+	if e := result.Days[2].Items[0]; !e.Overflow {
+		t.Errorf("todo larger than a day should be flagged: %+v", e)
+	}
+
+	var blocked []string
+	for _, it := range result.Blocked {
+		blocked = append(blocked, it.Todo.ID)
+	}
+	if !slices.Equal(blocked, []string{"f", "g"}) {
+		t.Errorf("blocked = %v", blocked)
+	}
+	if got := planIDs(result.AtRisk); !slices.Equal(got, []string{"b", "f"}) {
+		t.Errorf("at risk = %v", got)
+	}
+	if !result.AtRisk[1].Day.IsZero() {
+		t.Error("blocked todo should not have a planned day")
+	}
+}
+
+func TestPlanNothingOpen(t *testing.T) {
+	todos := Todos{{ID: "a", Title: "beres", Completed: true}}
+	result := todos.plan(Config{DailyCapacity: 60, DefaultEstimate: 30}, time.Now())
+	if len(result.Days) != 0 || len(result.Blocked) != 0 || len(result.AtRisk) != 0 {
+		t.Fatalf("plan = %+v", result)
+	}
+}
@@ -0,0 +1,446 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/aquasecurity/table"
+	"github.com/mattn/go-runewidth"
+	"golang.org/x/term"
+)
+
+// Theme isinya nama warna per bagian tabel, contoh "bold,cyan" atau
+// "bright-black". Mark dipakai sebagai penanda selesai / belum selesai
+type Theme struct {
+	Header   string
+	Done     string
+	Overdue  string
+	DueSoon  string
+	Tags     string
+	High     string
+	DoneMark string
+	OpenMark string
+}
+
+var themes = map[string]Theme{
+	"default": {Header: "bold,cyan", Done: "bright-black", Overdue: "bold,red", DueSoon: "yellow", Tags: "magenta", High: "red", DoneMark: "✔", OpenMark: "·"},
+	"dim":     {Header: "bold", Done: "dim", Overdue: "red", DueSoon: "", Tags: "dim", High: "bold", DoneMark: "✔", OpenMark: "·"},
+	"mono":    {DoneMark: "[x]", OpenMark: "[ ]"},
+}
+
+var ansiCodes = map[string]string{
+	"bold": "1", "dim": "2", "italic": "3", "underline": "4",
+	"black": "30", "red": "31", "green": "32", "yellow": "33",
+	"blue": "34", "magenta": "35", "cyan": "36", "white": "37",
+	"bright-black": "90", "bright-red": "91", "bright-green": "92", "bright-yellow": "93",
+	"bright-blue": "94", "bright-magenta": "95", "bright-cyan": "96", "bright-white": "97",
+}
+
+// merge pakai field dari o yang tidak kosong
+func (t Theme) merge(o Theme) Theme {
+	pick := func(a, b string) string {
+		if b != "" {
+			return b
+		}
+		return a
+	}
+	return Theme{
+		Header:   pick(t.Header, o.Header),
+		Done:     pick(t.Done, o.Done),
+		Overdue:  pick(t.Overdue, o.Overdue),
+		DueSoon:  pick(t.DueSoon, o.DueSoon),
+		Tags:     pick(t.Tags, o.Tags),
+		High:     pick(t.High, o.High),
+		DoneMark: pick(t.DoneMark, o.DoneMark),
+		OpenMark: pick(t.OpenMark, o.OpenMark),
+	}
+}
+
+// locale nerjemahin nama hari/bulan dari time.Format dan teks waktu relatif
+type locale struct {
+	names    []string // pasangan english -> lokal, nama panjang duluan
+	ago      string
+	dueIn    string
+	overdue  string
+	today    string
+	tomorrow string
+	justNow  string
+	units    [6]string // menit, jam, hari, minggu, bulan, tahun
+}
+
+var locales = map[string]locale{
+	"en": {
+		ago: "%s ago", dueIn: "due in %s", overdue: "overdue %s",
+		today: "due today", tomorrow: "due tomorrow", justNow: "just now",
+		units: [6]string{"m", "h", "d", "w", "mo", "y"},
+	},
+	"id": {
+		names: []string{
+			"Monday", "Senin", "Tuesday", "Selasa", "Wednesday", "Rabu", "Thursday", "Kamis",
+			"Friday", "Jumat", "Saturday", "Sabtu", "Sunday", "Minggu",
+			"January", "Januari", "February", "Februari", "March", "Maret", "April", "April",
+			"May", "Mei", "June", "Juni", "July", "Juli", "August", "Agustus",
+			"September", "September", "October", "Oktober", "November", "November", "December", "Desember",
+			"Mon", "Sen", "Tue", "Sel", "Wed", "Rab", "Thu", "Kam", "Fri", "Jum", "Sat", "Sab", "Sun", "Min",
+			"Jan", "Jan", "Feb", "Feb", "Mar", "Mar", "Apr", "Apr", "Jun", "Jun", "Jul", "Jul",
+			"Aug", "Agu", "Sep", "Sep", "Oct", "Okt", "Nov", "Nov", "Dec", "Des",
+		},
+		ago: "%s lalu", dueIn: "%s lagi", overdue: "telat %s",
+		today: "hari ini", tomorrow: "besok", justNow: "barusan",
+		units: [6]string{" mnt", " jam", " hari", " mgg", " bln", " thn"},
+	},
+}
+
+// tableColumns semua kolom yang bisa dipilih lewat -columns atau config
+var tableColumns = []string{"#", "id", "title", "status", "created", "completed", "updated", "due", "tags", "priority", "estimate"}
+
+var defaultColumns = []string{"#", "title", "status", "created", "completed", "due", "tags"}
+
+func validateColumns(cols []string) error {
+	for _, c := range cols {
+		known := false
+		for _, k := range tableColumns {
+			if c == k {
+				known = true
+				break
+			}
+		}
+		if !known {
+			return fmt.Errorf("unknown column %q, available: %s", c, strings.Join(tableColumns, ","))
+		}
+	}
+	return nil
+}
+
+type renderer struct {
+	cfg    Config
+	theme  Theme
+	locale locale
+	color  bool
+	now    time.Time
+}
+
+// colorEnabled ngikutin https://no-color.org dan mati kalau output bukan terminal
+func colorEnabled(f *os.File) bool {
+	if _, ok := os.LookupEnv("NO_COLOR"); ok {
+		return false
+	}
+	return term.IsTerminal(int(f.Fd()))
+}
+
+// terminalWidth balikin lebar terminal, atau $COLUMNS kalau output dipipe.
+// 0 artinya tidak dibatasi
+func terminalWidth(f *os.File) int {
+	if w, _, err := term.GetSize(int(f.Fd())); err == nil && w > 0 {
+		return w
+	}
+	if w, err := strconv.Atoi(os.Getenv("COLUMNS")); err == nil && w > 0 {
+		return w
+	}
+	return 0
+}
+
+func newRenderer(cfg Config, color bool, now time.Time) *renderer {
+	return &renderer{
+		cfg:    cfg,
+		theme:  themes[cfg.Theme].merge(cfg.Colors),
+		locale: locales[cfg.Locale],
+		color:  color,
+		now:    now,
+	}
+}
+
+func (r *renderer) paint(style, s string) string {
+	if !r.color || style == "" || s == "" {
+		return s
+	}
+	var codes []string
+	for _, name := range strings.Split(style, ",") {
+		if code, ok := ansiCodes[strings.TrimSpace(name)]; ok {
+			codes = append(codes, code)
+		}
+	}
+	if len(codes) == 0 {
+		return s
+	}
+	return "\x1b[" + strings.Join(codes, ";") + "m" + s + "\x1b[0m"
+}
+
+func (r *renderer) date(t time.Time) string {
+	return r.format(t, r.cfg.DateFormat)
+}
+
+// timePart bagian jam di layout DateFormat, dibuang kalau cuma butuh tanggal
+var timePart = regexp.MustCompile(`[ T]*(15|03|3):04(:05)?( ?(PM|pm))?`)
+
+// day format tanggal saja (tanpa jam) dengan DateFormat dan locale yang sama
+func (r *renderer) day(t time.Time) string {
+	layout := strings.Trim(timePart.ReplaceAllString(r.cfg.DateFormat, ""), " ,")
+	if layout == "" {
+		layout = "2006-01-02"
+	}
+	return r.format(t, layout)
+}
+
+// dueDate format due absolut, due yang cuma tanggal tampil tanpa jam
+func (r *renderer) dueDate(t time.Time) string {
+	if isDateOnly(t) {
+		return r.day(t)
+	}
+	return r.date(t)
+}
+
+func (r *renderer) format(t time.Time, layout string) string {
+	s := t.Format(layout)
+	if len(r.locale.names) > 0 {
+		s = strings.NewReplacer(r.locale.names...).Replace(s)
+	}
+	return s
+}
+
+// span ngubah durasi jadi bentuk pendek seperti 5m, 2h, 3d
+func (r *renderer) span(d time.Duration) string {
+	u := r.locale.units
+	switch {
+	case d < time.Hour:
+		return fmt.Sprintf("%d%s", int(d.Minutes()), u[0])
+	case d < 24*time.Hour:
+		return fmt.Sprintf("%d%s", int(d.Hours()), u[1])
+	case d < 14*24*time.Hour:
+		return fmt.Sprintf("%d%s", int(d.Hours()/24), u[2])
+	case d < 60*24*time.Hour:
+		return fmt.Sprintf("%d%s", int(d.Hours()/24/7), u[3])
+	case d < 365*24*time.Hour:
+		return fmt.Sprintf("%d%s", int(d.Hours()/24/30), u[4])
+	}
+	return fmt.Sprintf("%d%s", int(d.Hours()/24/365), u[5])
+}
+
+func (r *renderer) past(t time.Time) string {
+	if r.cfg.TimeFormat == "absolute" {
+		return r.date(t)
+	}
+	d := r.now.Sub(t)
+	if d < time.Minute {
+		return r.locale.justNow
+	}
+	return fmt.Sprintf(r.locale.ago, r.span(d))
+}
+
+func (r *renderer) due(t Todo) string {
+	due := *t.Due
+	var s string
+	switch {
+	case r.cfg.TimeFormat == "absolute":
+		s = r.date(due)
+	case isDateOnly(due):
+		days := int(math.Round(startOfDay(due).Sub(startOfDay(r.now)).Hours() / 24))
+		switch {
+		case days == 0:
+			s = r.locale.today
+		case days == 1:
+			s = r.locale.tomorrow
+		case days > 0:
+			s = fmt.Sprintf(r.locale.dueIn, r.span(time.Duration(days)*24*time.Hour))
+		default:
+			s = fmt.Sprintf(r.locale.overdue, r.span(time.Duration(-days)*24*time.Hour))
+		}
+	case due.After(r.now):
+		s = fmt.Sprintf(r.locale.dueIn, r.span(due.Sub(r.now)))
+	default:
+		s = fmt.Sprintf(r.locale.overdue, r.span(r.now.Sub(due)))
+	}
+
+	if t.Completed {
+		return s
+	}
+	switch {
+	case due.Before(r.now) && !(isDateOnly(due) && startOfDay(due).Equal(startOfDay(r.now))):
+		return r.paint(r.theme.Overdue, s)
+	case due.Before(r.now.Add(24 * time.Hour)):
+		return r.paint(r.theme.DueSoon, s)
+	}
+	return s
+}
+
+func (r *renderer) header(col string) string {
+	switch col {
+	case "#":
+		return "#"
+	case "id":
+		return "ID"
+	case "completed":
+		return "Completed At"
+	}
+	return strings.ToUpper(col[:1]) + col[1:]
+}
+
+func (r *renderer) cell(col string, index int, t Todo) string {
+	switch col {
+	case "#":
+		return strconv.Itoa(index)
+	case "id":
+		return t.ID
+	case "title":
+		return t.Title
+	case "status":
+		if t.Completed {
+			return r.theme.DoneMark
+		}
+		return r.theme.OpenMark
+	case "created":
+		return r.past(t.CreateAt)
+	case "completed":
+		if t.Completed && t.CompletedAt != nil {
+			return r.past(*t.CompletedAt)
+		}
+	case "updated":
+		if !t.UpdatedAt.IsZero() {
+			return r.past(t.UpdatedAt)
+		}
+	case "due":
+		if t.Due != nil {
+			return r.due(t)
+		}
+	case "tags":
+		return r.paint(r.theme.Tags, strings.Join(t.Tags, ","))
+	case "priority":
+		if t.Priority == PriorityHigh {
+			return r.paint(r.theme.High, t.Priority.String())
+		}
+		if t.Priority != PriorityNone {
+			return t.Priority.String()
+		}
+	case "estimate":
+		if t.Estimate > 0 {
+			return r.cfg.formatEstimate(t.Estimate)
+		}
+	}
+	return ""
+}
+
+// render nulis tabel todo. Kalau width > 0, judul dipotong (atau dibungkus
+// kalau cfg.Wrap) supaya tabel tidak lebih lebar dari terminal
+func (r *renderer) render(w io.Writer, todos Todos, cols []string, width int) {
+	rows := make([][]string, len(todos))
+	for i, t := range todos {
+		row := make([]string, len(cols))
+		for c, col := range cols {
+			row[c] = r.cell(col, i, t)
+		}
+		rows[i] = row
+	}
+
+	headers := make([]string, len(cols))
+	for c, col := range cols {
+		headers[c] = r.header(col)
+	}
+
+	titleCol := -1
+	for c, col := range cols {
+		if col == "title" {
+			titleCol = c
+		}
+	}
+
+	tbl := table.New(w)
+	tbl.SetRowLines(false)
+	tbl.SetHeaderStyle(table.StyleNormal)
+	if width > 0 && titleCol >= 0 {
+		// tiap kolom makan lebar isi + 2 padding + 1 garis, plus 1 garis paling kiri
+		used := 1
+		for c := range cols {
+			if c == titleCol {
+				used += 3
+				continue
+			}
+			max := runewidth.StringWidth(headers[c])
+			for _, row := range rows {
+				if n := visibleWidth(row[c]); n > max {
+					max = n
+				}
+			}
+			used += max + 3
+		}
+		titleWidth := width - used
+		if titleWidth < 10 {
+			titleWidth = 10
+		}
+		for _, row := range rows {
+			if r.cfg.Wrap {
+				row[titleCol] = wrapWords(row[titleCol], titleWidth)
+			} else {
+				row[titleCol] = runewidth.Truncate(row[titleCol], titleWidth, "…")
+			}
+		}
+	}
+	// lebar sudah diatur di atas, jangan biarkan tabel membungkus sendiri
+	tbl.SetAvailableWidth(1 << 16)
+	tbl.SetColumnMaxWidth(1 << 16)
+
+	for c := range headers {
+		headers[c] = r.paint(r.theme.Header, headers[c])
+	}
+	tbl.SetHeaders(headers...)
+	for i, row := range rows {
+		if todos[i].Completed && titleCol >= 0 {
+			row[titleCol] = r.paint(r.theme.Done, row[titleCol])
+		}
+		tbl.AddRow(row...)
+	}
+	tbl.Render()
+}
+
+// visibleWidth lebar teks tanpa kode warna ANSI
+func visibleWidth(s string) int {
+	var b strings.Builder
+	inEscape := false
+	for _, c := range s {
+		switch {
+		case c == '\x1b':
+			inEscape = true
+		case inEscape && c == 'm':
+			inEscape = false
+		case !inEscape:
+			b.WriteRune(c)
+		}
+	}
+	return runewidth.StringWidth(b.String())
+}
+
+// wrapWords bungkus teks per kata ke baris-baris selebar width,
+// kata yang lebih panjang dari width dipotong paksa
+func wrapWords(s string, width int) string {
+	var lines []string
+	line := ""
+	for _, word := range strings.Fields(s) {
+		for runewidth.StringWidth(word) > width {
+			if line != "" {
+				lines = append(lines, line)
+				line = ""
+			}
+			head := runewidth.Truncate(word, width, "")
+			lines = append(lines, head)
+			word = word[len(head):]
+		}
+		switch {
+		case line == "":
+			line = word
+		case runewidth.StringWidth(line)+1+runewidth.StringWidth(word) <= width:
+			line += " " + word
+		default:
+			lines = append(lines, line)
+			line = word
+		}
+	}
+	if line != "" {
+		lines = append(lines, line)
+	}
+	return strings.Join(lines, "\n")
+}
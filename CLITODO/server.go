@@ -0,0 +1,277 @@
+package main
+
+import (
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+)
+
+// server ngebuka store todos.json lewat JSON REST API kecil. Tiap request
+// ngunci file, load ulang, ubah, lalu save, sama persis dengan alur CLI,
+// jadi CLI dan server bisa jalan barengan tanpa saling timpa
+type server struct {
+	storage *Storage[Todos]
+	token   string
+}
+
+type apiTodo struct {
+	Index int
+	Todo
+}
+
+type apiInput struct {
+	Title *string
+	Due   *string
+	Tags  *[]string
+	Notes *string
+	Links *[]string
+}
+
+type apiError struct {
+	status int
+	msg    string
+}
+
+func (e *apiError) Error() string { return e.msg }
+
+func badRequest(format string, args ...any) error {
+	return &apiError{http.StatusBadRequest, fmt.Sprintf(format, args...)}
+}
+
+func runServe(args []string, storage *Storage[Todos]) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "127.0.0.1:7070", "Address to listen on")
+	token := fs.String("token", os.Getenv("TODO_API_TOKEN"), "Bearer token clients must send (default $TODO_API_TOKEN, random if empty)")
+	fs.Parse(args)
+
+	if *token == "" {
+		b := make([]byte, 16)
+		if _, err := rand.Read(b); err != nil {
+			return err
+		}
+		*token = hex.EncodeToString(b)
+		fmt.Println("Generated API token:", *token)
+	}
+
+	srv := &server{storage: storage, token: *token}
+	fmt.Printf("Serving %s on http://%s\n", storage.FileName, *addr)
+	httpServer := &http.Server{
+		Addr:              *addr,
+		Handler:           srv.routes(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	return httpServer.ListenAndServe()
+}
+
+func (s *server) routes() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /todos", s.handle(false, s.list))
+	mux.HandleFunc("POST /todos", s.handle(true, s.create))
+	mux.HandleFunc("GET /todos/{id}", s.handle(false, s.get))
+	mux.HandleFunc("PATCH /todos/{id}", s.handle(true, s.update))
+	mux.HandleFunc("POST /todos/{id}/toggle", s.handle(true, s.toggle))
+	mux.HandleFunc("DELETE /todos/{id}", s.handle(true, s.remove))
+	return s.auth(mux)
+}
+
+func (s *server) auth(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// wajib pakai skema "Bearer ", token polos tanpa prefix ditolak
+		got := r.Header.Get("Authorization")
+		if subtle.ConstantTimeCompare([]byte(got), []byte("Bearer "+s.token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, map[string]string{"error": "invalid or missing token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+type apiHandler func(r *http.Request, todos *Todos) (int, any, error)
+
+// handle bungkus handler dengan lock -> load -> handler -> save (kalau write)
+func (s *server) handle(write bool, h apiHandler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		unlock, err := s.storage.Lock()
+		if err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		defer unlock()
+
+		todos := Todos{}
+		if err := s.storage.Load(&todos); err != nil && !errors.Is(err, os.ErrNotExist) {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+		todos.ensureIDs()
+
+		status, body, err := h(r, &todos)
+		if err != nil {
+			var apiErr *apiError
+			if errors.As(err, &apiErr) {
+				writeJSON(w, apiErr.status, map[string]string{"error": apiErr.msg})
+				return
+			}
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+			return
+		}
+
+		if write {
+			if err := s.storage.Save(todos); err != nil {
+				writeJSON(w, http.StatusInternalServerError, map[string]string{"error": err.Error()})
+				return
+			}
+		}
+		writeJSON(w, status, body)
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	if status == http.StatusNoContent {
+		w.WriteHeader(status)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		log.Println("write response:", err)
+	}
+}
+
+func lookup(r *http.Request, todos *Todos) (int, error) {
+	index, err := todos.find(r.PathValue("id"))
+	if err != nil {
+		return -1, &apiError{http.StatusNotFound, err.Error()}
+	}
+	return index, nil
+}
+
+func decodeInput(r *http.Request) (apiInput, error) {
+	var in apiInput
+	dec := json.NewDecoder(r.Body)
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&in); err != nil {
+		return in, badRequest("invalid JSON body: %v", err)
+	}
+	return in, nil
+}
+
+// apply ngisi field yang dikirim client saja, field yang tidak ada dibiarkan
+func (in apiInput) apply(t *Todo) error {
+	if in.Title != nil {
+		if strings.TrimSpace(*in.Title) == "" {
+			return badRequest("Title must not be empty")
+		}
+		t.Title = *in.Title
+	}
+	if in.Due != nil {
+		if *in.Due == "" {
+			t.Due = nil
+		} else {
+			due, err := parseDue(*in.Due, time.Now())
+			if err != nil {
+				return badRequest("%v", err)
+			}
+			t.Due = &due
+		}
+	}
+	if in.Tags != nil {
+		t.Tags = parseTags(strings.Join(*in.Tags, ","))
+	}
+	if in.Notes != nil {
+		t.Notes = *in.Notes
+	}
+	if in.Links != nil {
+		links, err := parseLinks(*in.Links, t.Links)
+		if err != nil {
+			return badRequest("%v", err)
+		}
+		t.Links = links
+	}
+	return nil
+}
+
+func (s *server) list(r *http.Request, todos *Todos) (int, any, error) {
+	out := make([]apiTodo, 0, len(*todos))
+	for i, t := range *todos {
+		out = append(out, apiTodo{Index: i, Todo: t})
+	}
+	return http.StatusOK, out, nil
+}
+
+func (s *server) get(r *http.Request, todos *Todos) (int, any, error) {
+	index, err := lookup(r, todos)
+	if err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, apiTodo{Index: index, Todo: (*todos)[index]}, nil
+}
+
+func (s *server) create(r *http.Request, todos *Todos) (int, any, error) {
+	in, err := decodeInput(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	if in.Title == nil {
+		return 0, nil, badRequest("Title is required")
+	}
+	var t Todo
+	if err := in.apply(&t); err != nil {
+		return 0, nil, err
+	}
+	todo := todos.add(t.Title)
+	todo.Due = t.Due
+	todo.Tags = t.Tags
+	todo.Notes = t.Notes
+	todo.Links = t.Links
+	return http.StatusCreated, apiTodo{Index: len(*todos) - 1, Todo: *todo}, nil
+}
+
+func (s *server) update(r *http.Request, todos *Todos) (int, any, error) {
+	index, err := lookup(r, todos)
+	if err != nil {
+		return 0, nil, err
+	}
+	in, err := decodeInput(r)
+	if err != nil {
+		return 0, nil, err
+	}
+	t := (*todos)[index]
+	if err := in.apply(&t); err != nil {
+		return 0, nil, err
+	}
+	t.UpdatedAt = time.Now()
+	(*todos)[index] = t
+	return http.StatusOK, apiTodo{Index: index, Todo: t}, nil
+}
+
+func (s *server) toggle(r *http.Request, todos *Todos) (int, any, error) {
+	index, err := lookup(r, todos)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := todos.toggle(index); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusOK, apiTodo{Index: index, Todo: (*todos)[index]}, nil
+}
+
+func (s *server) remove(r *http.Request, todos *Todos) (int, any, error) {
+	index, err := lookup(r, todos)
+	if err != nil {
+		return 0, nil, err
+	}
+	if err := todos.delete(index); err != nil {
+		return 0, nil, err
+	}
+	return http.StatusNoContent, nil, nil
+}
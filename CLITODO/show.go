@@ -0,0 +1,81 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// show nampilin semua detail satu todo, termasuk notes dan link
+func (todos *Todos) show(w io.Writer, index int, cfg Config) {
+	t := (*todos)[index]
+	r := newRenderer(cfg, false, time.Now())
+	field := func(name, value string) {
+		if value != "" {
+			fmt.Fprintf(w, "%-11s %s\n", name+":", value)
+		}
+	}
+
+	fmt.Fprintf(w, "#%d %s\n", index, t.Title)
+	field("ID", t.ID)
+	status := "open"
+	if t.Completed {
+		status = "done"
+		if t.CompletedAt != nil {
+			status += " at " + r.date(*t.CompletedAt)
+		}
+	}
+	field("Status", status)
+	field("Created", r.date(t.CreateAt))
+	if !t.UpdatedAt.IsZero() {
+		field("Updated", r.date(t.UpdatedAt))
+	}
+	if t.Due != nil {
+		field("Due", r.dueDate(*t.Due))
+	}
+	if t.Priority != PriorityNone {
+		field("Priority", t.Priority.String())
+	}
+	if t.Estimate > 0 {
+		field("Estimate", cfg.formatEstimate(t.Estimate))
+	}
+	field("Tags", strings.Join(t.Tags, ", "))
+	if len(t.Sessions) > 0 {
+		total, partial := t.focusTotal()
+		line := fmt.Sprintf("%s in %d sessions", total.Round(time.Second), len(t.Sessions))
+		if partial > 0 {
+			line += fmt.Sprintf(" (%d partial)", partial)
+		}
+		field("Focused", line)
+	}
+
+	if len(t.DependsOn) > 0 {
+		fmt.Fprintln(w, "Depends on:")
+		for _, id := range t.DependsOn {
+			i, err := todos.find(id)
+			if err != nil {
+				fmt.Fprintf(w, "  - %s (deleted)\n", id)
+				continue
+			}
+			dep := (*todos)[i]
+			state := "open"
+			if dep.Completed {
+				state = "done"
+			}
+			fmt.Fprintf(w, "  - #%d %s (%s)\n", i, dep.Title, state)
+		}
+	}
+	if len(t.Links) > 0 {
+		fmt.Fprintln(w, "Links:")
+		for _, link := range t.Links {
+			fmt.Fprintf(w, "  - %s\n", link)
+		}
+	}
+	if t.Notes != "" {
+		fmt.Fprintln(w, "Notes:")
+		for _, line := range strings.Split(t.Notes, "\n") {
+			fmt.Fprintf(w, "  %s\n", line)
+		}
+	}
+}
@@ -3,6 +3,7 @@ package main
 import (
 	"encoding/json"
 	"os"
+	"path/filepath"
 )
 
 type Storage[T any] struct {
@@ -19,7 +20,29 @@ func (s *Storage[T]) Save(data T) error {
 		return err
 	}
 
-	return os.WriteFile(s.FileName, fileData, 0644)
+	// tulis ke file sementara dulu lalu rename, jadi todos.json tidak
+	// pernah setengah jadi kalau proses mati di tengah jalan
+	tmp, err := os.CreateTemp(filepath.Dir(s.FileName), filepath.Base(s.FileName)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(fileData); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), s.FileName)
 }
 
 func (s *Storage[T]) Load (data *T) error {
@@ -28,4 +51,20 @@ func (s *Storage[T]) Load (data *T) error {
 		return err
 	}
 	return json.Unmarshal(fileData, data)
-}
\ No newline at end of file
+}
+// Lock ngunci file data lewat <FileName>.lock sampai fungsi unlock dipanggil.
+// Lock otomatis lepas kalau prosesnya keluar, jadi tidak ada lock basi
+func (s *Storage[T]) Lock() (func(), error) {
+	f, err := os.OpenFile(s.FileName+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return func() {
+		unlockFile(f)
+		f.Close()
+	}, nil
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+	"time"
+)
+
+// Template adalah checklist yang sering diulang (release, onboarding, dll).
+// Disimpan sebagai <configDir>/templates/<nama>.json, contoh:
+//
+//	{
+//	    "Tags": ["release"],
+//	    "Items": [
+//	        {"Title": "Freeze branch v{{.version}}", "Due": "-2d"},
+//	        {"Title": "Publish v{{.version}}", "Due": "0d", "Tags": ["announce"]}
+//	    ]
+//	}
+//
+// Placeholder {{.nama}} diisi dari parameter nama=nilai, dan Due tiap item
+// adalah offset dari parameter due (default hari ini).
+type Template struct {
+	Tags  []string
+	Items []TemplateItem
+}
+
+type TemplateItem struct {
+	Title string
+	Due   string
+	Tags  []string
+}
+
+func templatesDir() string {
+	return filepath.Join(configDir(), "templates")
+}
+
+func listTemplates() ([]string, error) {
+	entries, err := os.ReadDir(templatesDir())
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var names []string
+	for _, e := range entries {
+		if !e.IsDir() && strings.HasSuffix(e.Name(), ".json") {
+			names = append(names, strings.TrimSuffix(e.Name(), ".json"))
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func loadTemplate(name string) (*Template, error) {
+	if strings.ContainsAny(name, `/\`) {
+		return nil, fmt.Errorf("invalid template name %q", name)
+	}
+	data, err := os.ReadFile(filepath.Join(templatesDir(), name+".json"))
+	if os.IsNotExist(err) {
+		return nil, fmt.Errorf("template %q not found in %s", name, templatesDir())
+	}
+	if err != nil {
+		return nil, err
+	}
+	var tmpl Template
+	if err := json.Unmarshal(data, &tmpl); err != nil {
+		return nil, fmt.Errorf("template %q: %w", name, err)
+	}
+	if len(tmpl.Items) == 0 {
+		return nil, fmt.Errorf("template %q has no items", name)
+	}
+	return &tmpl, nil
+}
+
+// expand ngisi placeholder, parameter yang tidak dikasih dianggap error
+func expand(text string, params map[string]string) (string, error) {
+	t, err := template.New("placeholder").Option("missingkey=error").Parse(text)
+	if err != nil {
+		return "", err
+	}
+	var b strings.Builder
+	if err := t.Execute(&b, params); err != nil {
+		return "", fmt.Errorf("%w (pass it as key=value)", err)
+	}
+	return b.String(), nil
+}
+
+// instantiate bikin semua todo dari template. Kalau satu item gagal,
+// tidak ada todo yang dibuat sama sekali
+func (tmpl *Template) instantiate(params map[string]string, now time.Time) ([]Todo, error) {
+	anchor := startOfDay(now)
+	hasAnchor := false
+	if due, ok := params["due"]; ok {
+		t, err := parseDue(due, now)
+		if err != nil {
+			return nil, err
+		}
+		anchor = t
+		hasAnchor = true
+	}
+
+	var items []Todo
+	for i, item := range tmpl.Items {
+		title, err := expand(item.Title, params)
+		if err != nil {
+			return nil, fmt.Errorf("item %d: %w", i+1, err)
+		}
+		if strings.TrimSpace(title) == "" {
+			return nil, fmt.Errorf("item %d: empty title", i+1)
+		}
+
+		todo := Todo{
+			ID:        newID(),
+			Title:     title,
+			CreateAt:  now,
+			UpdatedAt: now,
+		}
+
+		var tags []string
+		for _, tag := range append(append([]string{}, tmpl.Tags...), item.Tags...) {
+			expanded, err := expand(tag, params)
+			if err != nil {
+				return nil, fmt.Errorf("item %d: %w", i+1, err)
+			}
+			tags = append(tags, expanded)
+		}
+		todo.Tags = parseTags(strings.Join(tags, ","))
+
+		if item.Due != "" {
+			due, ok := applyOffset(anchor, strings.ToLower(strings.TrimSpace(item.Due)))
+			if !ok {
+				return nil, fmt.Errorf("item %d: invalid due offset %q, use e.g. -2d, 0d, +1w or +4h", i+1, item.Due)
+			}
+			todo.Due = &due
+		} else if hasAnchor {
+			due := anchor
+			todo.Due = &due
+		}
+
+		items = append(items, todo)
+	}
+	return items, nil
+}
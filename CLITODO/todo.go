@@ -1,32 +1,101 @@
 package main
 
 import (
+	"crypto/rand"
+	"crypto/sha1"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"os"
 	"strconv"
+	"strings"
 	"time"
-
-	"github.com/aquasecurity/table"
 )
 type Todo struct {
+	ID string
 	Title string
 	Completed bool
 	CreateAt time.Time
 	CompletedAt *time.Time
+	Due *time.Time `json:",omitempty"`
+	Tags []string `json:",omitempty"`
+	Priority Priority `json:",omitempty"`
+	Estimate int `json:",omitempty"`
+	DependsOn []string `json:",omitempty"`
+	Notes string `json:",omitempty"`
+	Links []string `json:",omitempty"`
+	Sessions []Session `json:",omitempty"`
+	UpdatedAt time.Time `json:",omitzero"`
 }
 
 type Todos []Todo
 
-func (todos *Todos) add(title string) {
+type Priority int
+
+const (
+	PriorityNone Priority = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+)
+
+var priorityNames = []string{"none", "low", "medium", "high"}
+
+func (p Priority) String() string {
+	if p < PriorityNone || p > PriorityHigh {
+		return strconv.Itoa(int(p))
+	}
+	return priorityNames[p]
+}
+
+func parsePriority(s string) (Priority, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+	for i, name := range priorityNames {
+		if s == name || s == name[:1] || s == strconv.Itoa(i) {
+			return Priority(i), nil
+		}
+	}
+	if s == "med" {
+		return PriorityMedium, nil
+	}
+	return PriorityNone, fmt.Errorf("invalid priority %q, use none, low, medium or high", s)
+}
+
+// newID bikin id pendek yang tetap sama selama todo hidup,
+// dipakai buat UID kalender dan referensi lain selain index
+func newID() string {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return strconv.FormatInt(time.Now().UnixNano(), 36)
+	}
+	return hex.EncodeToString(b)
+}
+
+func (todos *Todos) add(title string) *Todo {
 	todo := Todo{
+		ID: newID(),
 		Title: title,
 		Completed: false,
 		CompletedAt: nil,
 		CreateAt: time.Now(),
 	}
+	todo.UpdatedAt = todo.CreateAt
 
 	*todos = append(*todos, todo)
+	return &(*todos)[len(*todos)-1]
+}
+
+// ensureIDs kasih id ke todo lama yang disimpan sebelum ada field ID.
+// ID-nya diturunkan dari CreateAt, jadi salinan todos.json yang sama
+// (misal hasil sync) tetap dapat ID yang sama
+func (todos *Todos) ensureIDs() {
+	t := *todos
+	for i := range t {
+		if t[i].ID == "" {
+			sum := sha1.Sum([]byte(t[i].CreateAt.Format(time.RFC3339Nano)))
+			t[i].ID = hex.EncodeToString(sum[:4])
+		}
+	}
 }
 
 func (todos *Todos) validateIndex(index int) error {
@@ -38,6 +107,21 @@ func (todos *Todos) validateIndex(index int) error {
 	return nil
 }
 
+// find nyari todo dari index (angka) atau ID, biar perintah lain bisa
+// pakai ID yang stabil walaupun urutan list berubah
+func (todos *Todos) find(ref string) (int, error) {
+	for i, t := range *todos {
+		if t.ID == ref {
+			return i, nil
+		}
+	}
+	index, err := strconv.Atoi(ref)
+	if err != nil || index < 0 || index >= len(*todos) {
+		return -1, fmt.Errorf("todo %q not found", ref)
+	}
+	return index, nil
+}
+
 func (todos *Todos) delete(index int) error {
 	t := *todos
 	if err := t.validateIndex(index); err != nil {
@@ -59,6 +143,7 @@ func (todos *Todos) toggle(index int) error {
 		t[index].CompletedAt = &completionTime
 	}
 	t[index].Completed = !isCOmpleted
+	t[index].UpdatedAt = time.Now()
 
 	return nil
 }
@@ -71,27 +156,55 @@ func (todos *Todos) edit(index int, title string) error {
 	}
 
 	t[index].Title = title
+	t[index].UpdatedAt = time.Now()
 
 	return nil
 }
 
-func (todos *Todos) print() {
-	table := table.New(os.Stdout)
-	table.SetRowLines(false)
-	table.SetHeaders("#", "TItle", "Completed", "Create At", "Completed At")
-	for index, t := range *todos {
-		completed := "X"
-		completedAt := ""
-
-		if t.Completed {
-			completed = "V"
-			if t.CompletedAt != nil {
-				completedAt = t.CompletedAt.Format(time.RFC1123)
-			}
-		}
+// lastModified dipakai buat nentuin versi mana yang lebih baru waktu merge.
+// Data lama belum punya UpdatedAt, jadi pakai CreateAt/CompletedAt
+func (t Todo) lastModified() time.Time {
+	last := t.UpdatedAt
+	if t.CreateAt.After(last) {
+		last = t.CreateAt
+	}
+	if t.CompletedAt != nil && t.CompletedAt.After(last) {
+		last = *t.CompletedAt
+	}
+	return last
+}
 
-		table.AddRow(strconv.Itoa(index), t.Title, completed, t.CreateAt.Format(time.RFC1123), completedAt )
+// parseTags misahin "a, b,c" jadi tag yang sudah di-trim, tanpa duplikat
+func parseTags(s string) []string {
+	var tags []string
+	seen := map[string]bool{}
+	for _, tag := range strings.Split(s, ",") {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		tags = append(tags, tag)
 	}
-	table.Render()
+	return tags
 }
 
+// print nampilin tabel todo sesuai config.json (kolom, tema, format waktu).
+// columns dari flag -columns, kosong artinya pakai config
+func (todos *Todos) print(columns string) {
+	cfg, err := loadConfig()
+	if err != nil {
+		fmt.Println("Warning:", err)
+	}
+	cols := cfg.Columns
+	if columns != "" {
+		cols = parseTags(columns)
+		if err := validateColumns(cols); err != nil {
+			fmt.Println("Error:", err)
+			return
+		}
+	}
+
+	r := newRenderer(cfg, colorEnabled(os.Stdout), time.Now())
+	r.render(os.Stdout, *todos, cols, terminalWidth(os.Stdout))
+}
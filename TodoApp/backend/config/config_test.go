@@ -0,0 +1,41 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func prodConfig(t *testing.T, dsn string) Config {
+	t.Helper()
+	cfg, err := profile(EnvProd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Database.DSN = dsn
+	cfg.Auth.JWTSecret = strings.Repeat("x", 32)
+	cfg.Server.CORSOrigins = []string{"https://todo.example.com"}
+	return cfg
+}
+
+func TestValidateProdRejectsDefaultDBPassword(t *testing.T) {
+	for _, dsn := range []string{
+		devDSN,
+		"host=db user=app password=sigma123 dbname=todos",
+		"host=db user=app password='sigma123' dbname=todos",
+		"postgres://sigma:sigma123@db:5432/todosapp?sslmode=require",
+		"postgresql://app:sigma123@db/todos",
+		"postgres://app@db/todos?password=sigma123",
+	} {
+		if err := prodConfig(t, dsn).Validate(); err == nil || !strings.Contains(err.Error(), "default database credentials") {
+			t.Errorf("%s: err = %v", dsn, err)
+		}
+	}
+	for _, dsn := range []string{
+		"host=db user=app password=s3cret dbname=todos",
+		"postgres://app:s3cret@db:5432/todos",
+	} {
+		if err := prodConfig(t, dsn).Validate(); err != nil {
+			t.Errorf("%s: %v", dsn, err)
+		}
+	}
+}
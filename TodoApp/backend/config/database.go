@@ -2,15 +2,35 @@ package config
 
 import (
 	"log"
+	"strings"
 
+	"github.com/glebarez/sqlite"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
 )
 
 var DB *gorm.DB
-func ConnectDatabase() {
-	connect := "host=localhost user=sigma password=sigma123 sslmode=disable dbname=todosapp"
-	database, err := gorm.Open(postgres.Open(connect), &gorm.Config{})
+
+// ConnectDatabase buka koneksi sesuai Config.Database. SQLite pakai driver
+// pure Go (tanpa cgo) dan dikasih busy_timeout biar worker yang nulis bareng
+// tidak langsung gagal "database is locked"
+func ConnectDatabase(db DatabaseConfig) {
+	var dialector gorm.Dialector
+	switch db.Driver {
+	case DriverSQLite:
+		dsn := db.DSN
+		if !strings.Contains(dsn, "busy_timeout") {
+			sep := "?"
+			if strings.Contains(dsn, "?") {
+				sep = "&"
+			}
+			dsn += sep + "_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+		}
+		dialector = sqlite.Open(dsn)
+	default:
+		dialector = postgres.Open(db.DSN)
+	}
+	database, err := gorm.Open(dialector, &gorm.Config{})
 	if err != nil {
 		log.Fatal("gagal terhubung dengan database  ", err)
 	}
@@ -0,0 +1,144 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"todo/config"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+// adminUser data user buat admin, tanpa hash password
+type adminUser struct {
+	ID        uint   `json:"id"`
+	Username  string `json:"username"`
+	Role      string `json:"role"`
+	Disabled  bool   `json:"disabled"`
+	CreatedAt string `json:"created_at"`
+	TodoCount int64  `json:"todo_count"`
+}
+
+// ListUsers GET /admin/users?q=nama&page=1&limit=20
+func ListUsers(c *gin.Context) {
+	page, _ := strconv.Atoi(c.DefaultQuery("page", "1"))
+	limit, _ := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if page < 1 {
+		page = 1
+	}
+	if limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	query := config.DB.Model(&models.User{})
+	if q := c.Query("q"); q != "" {
+		query = query.Where("LOWER(username) LIKE ?", "%"+strings.ToLower(q)+"%")
+	}
+	if role := c.Query("role"); role != "" {
+		query = query.Where("role = ?", role)
+	}
+
+	var total int64
+	if err := query.Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+
+	var users []models.User
+	if err := query.Order("id").Offset((page - 1) * limit).Limit(limit).Find(&users).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+
+	result := make([]adminUser, 0, len(users))
+	for _, u := range users {
+		var count int64
+		config.DB.Model(&models.Todo{}).Where("user_id = ?", u.ID).Count(&count)
+		result = append(result, adminUser{
+			ID:        u.ID,
+			Username:  u.Username,
+			Role:      u.Role,
+			Disabled:  u.Disabled,
+			CreatedAt: u.CreatedAt.Format("2006-01-02 15:04:05"),
+			TodoCount: count,
+		})
+	}
+	c.JSON(http.StatusOK, gin.H{"users" : result, "total" : total, "page" : page, "limit" : limit})
+}
+
+type setDisabled struct {
+	Disabled *bool `json:"disabled" binding:"required"`
+}
+
+// SetUserDisabled PUT /admin/users/:id/disable body {"disabled": true}
+func SetUserDisabled(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var input setDisabled
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	// jangan sampai admin ngunci dirinya sendiri
+	if uint(id) == c.MustGet("userID").(uint) {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "You cannot disable your own account"})
+		return
+	}
+
+	var user models.User
+	if err := config.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "User not found"})
+		return
+	}
+	if err := config.DB.Model(&user).Update("disabled", *input.Disabled).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update user", "username" : user.Username, "disabled" : *input.Disabled})
+}
+
+// GetUserTodos GET /admin/users/:id/todos
+func GetUserTodos(c *gin.Context) {
+	id, _ := strconv.Atoi(c.Param("id"))
+	var user models.User
+	if err := config.DB.First(&user, id).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "User not found"})
+		return
+	}
+	var todos []models.Todo
+	if err := config.DB.Where("user_id = ?", user.ID).Find(&todos).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"username" : user.Username, "todos" : todos})
+}
+
+// GetStats GET /admin/stats, ringkasan seluruh sistem
+func GetStats(c *gin.Context) {
+	var users, disabled, admins, todos int64
+	config.DB.Model(&models.User{}).Count(&users)
+	config.DB.Model(&models.User{}).Where("disabled = ?", true).Count(&disabled)
+	config.DB.Model(&models.User{}).Where("role = ?", models.RoleAdmin).Count(&admins)
+	config.DB.Model(&models.Todo{}).Count(&todos)
+
+	var rows []struct {
+		Status models.Status
+		Count  int64
+	}
+	if err := config.DB.Model(&models.Todo{}).Select("status, count(*) as count").Group("status").Scan(&rows).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	byStatus := gin.H{}
+	for _, r := range rows {
+		byStatus[string(r.Status)] = r.Count
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"users":          users,
+		"disabled_users": disabled,
+		"admins":         admins,
+		"todos":          todos,
+		"todos_by_status": byStatus,
+	})
+}
@@ -0,0 +1,230 @@
+package controller
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+	"todo/config"
+	"todo/models"
+	"todo/storage"
+	"todo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// batas upload, bisa diganti lewat SetUploadLimits dari main
+var (
+	maxUploadBytes int64 = 10 << 20
+	allowedUploads       = map[string]bool{
+		"image/png":       true,
+		"image/jpeg":      true,
+		"image/gif":       true,
+		"image/webp":      true,
+		"application/pdf": true,
+		"text/plain":      true,
+		"application/zip": true,
+	}
+)
+
+// fileURLTTL umur link download yang ditandatangani
+const fileURLTTL = 15 * time.Minute
+
+// SetUploadLimits ganti ukuran maksimal (byte) dan daftar tipe file yang
+// boleh di-upload. Nilai kosong berarti tetap pakai default
+func SetUploadLimits(maxBytes int64, types []string) {
+	if maxBytes > 0 {
+		maxUploadBytes = maxBytes
+	}
+	if len(types) > 0 {
+		allowedUploads = map[string]bool{}
+		for _, t := range types {
+			allowedUploads[strings.ToLower(strings.TrimSpace(t))] = true
+		}
+	}
+}
+
+// findAttachment ambil lampiran :attID di bawah todo :id, user minimal need
+func findAttachment(c *gin.Context, todo *models.Todo, att *models.Attachment, need string) bool {
+	if !findTodo(c, todo, need) {
+		return false
+	}
+	attID, err := strconv.Atoi(c.Param("attID"))
+	if err != nil || config.DB.Where("todo_id = ?", todo.ID).First(att, attID).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Lampiran tidak di temukan"})
+		return false
+	}
+	return true
+}
+
+func storageKey(todoID uint) string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return fmt.Sprintf("todos/%d/%s", todoID, hex.EncodeToString(b))
+}
+
+// GetAttachments GET /todo/:id/attachments
+func GetAttachments(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListViewer) {
+		return
+	}
+	var attachments []models.Attachment
+	if err := config.DB.Where("todo_id = ?", todo.ID).Order("id").Find(&attachments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"attachments" : attachments})
+}
+
+// UploadAttachment POST /todo/:id/attachments, multipart dengan field "file".
+// Tipe file dicek dari isinya, bukan dari nama atau header yang dikirim client
+func UploadAttachment(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	// sisa 1MB buat boundary dan header multipart
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxUploadBytes+1<<20)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error" : fmt.Sprintf("File is larger than %d MB", maxUploadBytes>>20)})
+			return
+		}
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Field file is required"})
+		return
+	}
+	if header.Size > maxUploadBytes {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error" : fmt.Sprintf("File is larger than %d MB", maxUploadBytes>>20)})
+		return
+	}
+	if header.Size == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "File is empty"})
+		return
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+		return
+	}
+	defer file.Close()
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+		return
+	}
+	head = head[:n]
+	contentType, _, _ := mime.ParseMediaType(http.DetectContentType(head))
+	if !allowedUploads[contentType] {
+		c.JSON(http.StatusUnsupportedMediaType, gin.H{"error" : "File type " + contentType + " is not allowed"})
+		return
+	}
+
+	att := models.Attachment{
+		TodoID:      todo.ID,
+		UploaderID:  c.MustGet("userID").(uint),
+		FileName:    filepath.Base(header.Filename),
+		ContentType: contentType,
+		Size:        header.Size,
+		StorageKey:  storageKey(todo.ID),
+	}
+	body := io.MultiReader(bytes.NewReader(head), file)
+	if err := storage.Default.Put(c.Request.Context(), att.StorageKey, body, att.Size, att.ContentType); err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error" : "Gagal menyimpan file: " + err.Error()})
+		return
+	}
+	if err := config.DB.Create(&att).Error; err != nil {
+		storage.Default.Delete(c.Request.Context(), att.StorageKey)
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil upload lampiran", "attachment" : att})
+}
+
+// sendAttachment kirim isi file sebagai download. nosniff biar browser tidak
+// menebak tipe lain (misal html) dari isi file
+func sendAttachment(c *gin.Context, att models.Attachment) {
+	r, err := storage.Default.Get(c.Request.Context(), att.StorageKey)
+	if errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "File tidak di temukan"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error" : err.Error()})
+		return
+	}
+	defer r.Close()
+	c.DataFromReader(http.StatusOK, att.Size, att.ContentType, r, map[string]string{
+		"Content-Disposition":    mime.FormatMediaType("attachment", map[string]string{"filename": att.FileName}),
+		"X-Content-Type-Options": "nosniff",
+	})
+}
+
+// DownloadAttachment GET /todo/:id/attachments/:attID
+func DownloadAttachment(c *gin.Context) {
+	var todo models.Todo
+	var att models.Attachment
+	if !findAttachment(c, &todo, &att, models.ListViewer) {
+		return
+	}
+	sendAttachment(c, att)
+}
+
+// AttachmentURL GET /todo/:id/attachments/:attID/url, link download yang
+// bisa dibuka tanpa token selama fileURLTTL
+func AttachmentURL(c *gin.Context) {
+	var todo models.Todo
+	var att models.Attachment
+	if !findAttachment(c, &todo, &att, models.ListViewer) {
+		return
+	}
+	expires := time.Now().Add(fileURLTTL)
+	url := fmt.Sprintf("/files/%d?expires=%d&sig=%s", att.ID, expires.Unix(), utils.SignFile(att.ID, expires))
+	c.JSON(http.StatusOK, gin.H{"url" : url, "expires_at" : expires})
+}
+
+// ServeSignedFile GET /files/:attID?expires=&sig=, tanpa login
+func ServeSignedFile(c *gin.Context) {
+	attID, err := strconv.Atoi(c.Param("attID"))
+	if err != nil || !utils.VerifyFile(uint(attID), c.Query("expires"), c.Query("sig")) {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "Link tidak valid atau sudah kadaluarsa"})
+		return
+	}
+	var att models.Attachment
+	if config.DB.First(&att, attID).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Lampiran tidak di temukan"})
+		return
+	}
+	sendAttachment(c, att)
+}
+
+// DeleteAttachment DELETE /todo/:id/attachments/:attID
+func DeleteAttachment(c *gin.Context) {
+	var todo models.Todo
+	var att models.Attachment
+	if !findAttachment(c, &todo, &att, models.ListEditor) {
+		return
+	}
+	if err := storage.Default.Delete(c.Request.Context(), att.StorageKey); err != nil && !errors.Is(err, storage.ErrNotFound) {
+		c.JSON(http.StatusBadGateway, gin.H{"error" : err.Error()})
+		return
+	}
+	if err := config.DB.Unscoped().Delete(&att).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menghapus lampiran"})
+}
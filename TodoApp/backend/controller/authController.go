@@ -31,6 +31,7 @@ func Register(c *gin.Context) {
 	user := models.User{
 		Username: input.Username,
 		Password: string(hashPw),
+		Role: models.RoleUser,
 	}
 
 	if err := config.DB.Create(&user).Error; err != nil {
@@ -59,8 +60,12 @@ func Login (c *gin.Context) {
 		c.JSON(http.StatusUnauthorized, gin.H{"error" : "Password incorrect"})
 		return
 	}
+	if user.Disabled {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "Account disabled"})
+		return
+	}
 
-	 token, err := utils.GenerateToken(user.ID, user.Username); 
+	 token, err := utils.GenerateToken(user.ID, user.Username, user.Role); 
 	 if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error" : "Failed create token"})
 		return
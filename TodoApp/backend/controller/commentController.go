@@ -0,0 +1,186 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"todo/config"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+const maxCommentLength = 5000
+
+// commentNode komentar beserta balasannya
+type commentNode struct {
+	models.Comment
+	Replies []*commentNode `json:"balasan"`
+}
+
+// findComment ambil komentar :commentID di bawah todo :id yang bisa dilihat user
+func findComment(c *gin.Context, todo *models.Todo, comment *models.Comment) bool {
+	if !findTodo(c, todo, models.ListViewer) {
+		return false
+	}
+	commentID, err := strconv.Atoi(c.Param("commentID"))
+	if err != nil || config.DB.Where("todo_id = ?", todo.ID).First(comment, commentID).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Komentar tidak di temukan"})
+		return false
+	}
+	return true
+}
+
+// GetComments GET /todo/:id/comments, komentar disusun jadi thread,
+// urut dari yang paling lama
+func GetComments(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListViewer) {
+		return
+	}
+	var comments []models.Comment
+	if err := config.DB.Where("todo_id = ?", todo.ID).Order("created_at, id").Find(&comments).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+
+	nodes := make(map[uint]*commentNode, len(comments))
+	for _, comment := range comments {
+		nodes[comment.ID] = &commentNode{Comment: comment, Replies: []*commentNode{}}
+	}
+	thread := []*commentNode{}
+	for _, comment := range comments {
+		node := nodes[comment.ID]
+		if parent, ok := nodes[derefID(comment.ParentID)]; ok {
+			parent.Replies = append(parent.Replies, node)
+		} else {
+			thread = append(thread, node)
+		}
+	}
+	c.JSON(http.StatusOK, gin.H{"comments" : thread, "total" : len(comments)})
+}
+
+func derefID(id *uint) uint {
+	if id == nil {
+		return 0
+	}
+	return *id
+}
+
+type commentInput struct {
+	Body     string `json:"isi"`
+	ParentID *uint  `json:"parent_id"`
+}
+
+func commentBody(c *gin.Context, body string) (string, bool) {
+	body = strings.TrimSpace(body)
+	if body == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Comment cannot be empty"})
+		return "", false
+	}
+	if len(body) > maxCommentLength {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Comment is too long"})
+		return "", false
+	}
+	return body, true
+}
+
+// CreateComment POST /todo/:id/comments body {"isi": "...", "parent_id": 3}.
+// Viewer juga boleh komentar, diskusi bukan mengubah todo
+func CreateComment(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListViewer) {
+		return
+	}
+	var input commentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	body, ok := commentBody(c, input.Body)
+	if !ok {
+		return
+	}
+	if input.ParentID != nil {
+		var parent models.Comment
+		if config.DB.Where("todo_id = ?", todo.ID).First(&parent, *input.ParentID).Error != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : "Parent comment not found on this todo"})
+			return
+		}
+	}
+
+	comment := models.Comment{
+		TodoID:   todo.ID,
+		AuthorID: c.MustGet("userID").(uint),
+		ParentID: input.ParentID,
+		Body:     body,
+	}
+	if err := config.DB.Create(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil menambah komentar", "comment" : comment})
+}
+
+// UpdateComment PUT /todo/:id/comments/:commentID, cuma penulisnya yang boleh edit
+func UpdateComment(c *gin.Context) {
+	var todo models.Todo
+	var comment models.Comment
+	if !findComment(c, &todo, &comment) {
+		return
+	}
+	if comment.AuthorID != c.MustGet("userID").(uint) {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "Only the author can edit this comment"})
+		return
+	}
+	if comment.Removed {
+		c.JSON(http.StatusConflict, gin.H{"error" : "Comment has been deleted"})
+		return
+	}
+	var input commentInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	body, ok := commentBody(c, input.Body)
+	if !ok {
+		return
+	}
+	comment.Body = body
+	comment.Edited = true
+	if err := config.DB.Save(&comment).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update komentar", "comment" : comment})
+}
+
+// DeleteComment DELETE /todo/:id/comments/:commentID, boleh oleh penulisnya
+// atau owner list. Kalau sudah ada balasan, isinya saja yang dikosongkan
+// biar thread-nya tidak putus
+func DeleteComment(c *gin.Context) {
+	var todo models.Todo
+	var comment models.Comment
+	if !findComment(c, &todo, &comment) {
+		return
+	}
+	userID := c.MustGet("userID").(uint)
+	if comment.AuthorID != userID && models.MemberRole(config.DB, todo.ListID, userID) != models.ListOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "Only the author or the list owner can delete this comment"})
+		return
+	}
+
+	var replies int64
+	config.DB.Model(&models.Comment{}).Where("parent_id = ?", comment.ID).Count(&replies)
+	var err error
+	if replies > 0 {
+		err = config.DB.Model(&comment).Updates(map[string]any{"body": "", "removed": true}).Error
+	} else {
+		err = config.DB.Delete(&comment).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menghapus komentar"})
+}
@@ -0,0 +1,290 @@
+package controller
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+	"todo/config"
+	"todo/events"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findLabel ambil label :labelID milik user
+func findLabel(c *gin.Context, label *models.Label, param string) bool {
+	id, err := strconv.Atoi(c.Param(param))
+	userID := c.MustGet("userID").(uint)
+	if err != nil || config.DB.Where("owner_id = ?", userID).First(label, id).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Label tidak di temukan"})
+		return false
+	}
+	return true
+}
+
+// labelNameTaken true kalau user sudah punya label lain dengan nama itu
+func labelNameTaken(userID uint, name string, except uint) bool {
+	var count int64
+	config.DB.Model(&models.Label{}).Where("owner_id = ? AND name = ? AND id <> ?", userID, name, except).Count(&count)
+	return count > 0
+}
+
+// labelTodos todo yang memakai label
+func labelTodos(tx *gorm.DB, labelID uint) ([]models.Todo, error) {
+	var todos []models.Todo
+	err := tx.Where("id IN (?)", models.LabelTodoIDs(tx, []uint{labelID})).Find(&todos).Error
+	return todos, err
+}
+
+// touchTodos update updated_at todo yang labelnya berubah, muat ulang
+// labelnya dan catat todo.updated ke outbox webhook di tx yang sama. Setelah
+// commit todos dipublish ke stream, biar client yang sync atau dengar stream
+// ikut dapat nama/warna baru
+func touchTodos(tx *gorm.DB, todos []models.Todo) error {
+	if len(todos) == 0 {
+		return nil
+	}
+	ids := make([]uint, 0, len(todos))
+	for _, todo := range todos {
+		ids = append(ids, todo.ID)
+	}
+	now := time.Now()
+	if err := tx.Model(&models.Todo{}).Where("id IN ?", ids).Update("updated_at", now).Error; err != nil {
+		return err
+	}
+	for i := range todos {
+		todos[i].UpdatedAt = now
+		todos[i].Labels = []models.Label{}
+		if err := tx.Model(&todos[i]).Association("Labels").Find(&todos[i].Labels); err != nil {
+			return err
+		}
+		if err := outboxTodo(tx, events.TodoUpdated, todos[i], todos[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func publishTodos(todos []models.Todo) {
+	for _, todo := range todos {
+		publishTodo(events.TodoUpdated, todo)
+	}
+}
+
+type labelWithCount struct {
+	models.Label
+	Todos int64 `json:"jumlah_todo"`
+}
+
+// GetLabels GET /labels, label milik user beserta jumlah todo yang memakainya
+func GetLabels(c *gin.Context) {
+	var labels []labelWithCount
+	err := config.DB.Model(&models.Label{}).
+		Select("labels.*, COUNT(todos.id) AS todos").
+		Joins("LEFT JOIN todo_labels ON todo_labels.label_id = labels.id").
+		Joins("LEFT JOIN todos ON todos.id = todo_labels.todo_id AND todos.deleted_at IS NULL").
+		Where("labels.owner_id = ?", c.MustGet("userID").(uint)).
+		Group("labels.id").
+		Order("labels.name").
+		Scan(&labels).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"labels" : labels})
+}
+
+type labelInput struct {
+	Name  *string `json:"nama"`
+	Color *string `json:"warna"`
+}
+
+// applyLabelInput validasi nama/warna lalu isi ke label
+func applyLabelInput(c *gin.Context, label *models.Label, input labelInput) bool {
+	if input.Name != nil {
+		name := models.NormalizeLabelName(*input.Name)
+		if name == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : "Label name cannot be empty"})
+			return false
+		}
+		if labelNameTaken(label.OwnerID, name, label.ID) {
+			c.JSON(http.StatusConflict, gin.H{"error" : "You already have a label named " + name + ", merge them instead"})
+			return false
+		}
+		label.Name = name
+	}
+	if input.Color != nil {
+		if !models.ValidColor(*input.Color) {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : "Color must look like #1e88e5"})
+			return false
+		}
+		label.Color = *input.Color
+	}
+	return true
+}
+
+// CreateLabel POST /labels body {"nama": "kerja", "warna": "#1e88e5"}
+func CreateLabel(c *gin.Context) {
+	var input labelInput
+	if err := c.ShouldBindJSON(&input); err != nil || input.Name == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	label := models.Label{OwnerID: c.MustGet("userID").(uint), Color: models.DefaultLabelColor}
+	if !applyLabelInput(c, &label, input) {
+		return
+	}
+	if err := config.DB.Create(&label).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil membuat label", "label" : label})
+}
+
+// UpdateLabel PUT /labels/:labelID, ganti nama dan/atau warna. Todo yang
+// memakai label ikut berubah karena nyambungnya lewat id
+func UpdateLabel(c *gin.Context) {
+	var label models.Label
+	if !findLabel(c, &label, "labelID") {
+		return
+	}
+	var input labelInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	if !applyLabelInput(c, &label, input) {
+		return
+	}
+	var todos []models.Todo
+	err := config.DB.Transaction(func(tx *gorm.DB) (err error) {
+		if err = tx.Save(&label).Error; err != nil {
+			return err
+		}
+		if todos, err = labelTodos(tx, label.ID); err != nil {
+			return err
+		}
+		return touchTodos(tx, todos)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	publishTodos(todos)
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update label", "label" : label})
+}
+
+// DeleteLabel DELETE /labels/:labelID, label dilepas dari semua todo
+func DeleteLabel(c *gin.Context) {
+	var label models.Label
+	if !findLabel(c, &label, "labelID") {
+		return
+	}
+	var todos []models.Todo
+	err := config.DB.Transaction(func(tx *gorm.DB) (err error) {
+		if todos, err = labelTodos(tx, label.ID); err != nil {
+			return err
+		}
+		if err = models.DeleteLabel(tx, label.ID); err != nil {
+			return err
+		}
+		return touchTodos(tx, todos)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	publishTodos(todos)
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menghapus label"})
+}
+
+type mergeLabelInput struct {
+	Into uint `json:"into" binding:"required"`
+}
+
+// MergeLabel POST /labels/:labelID/merge body {"into": 5}, semua todo dengan
+// label :labelID pindah ke label 5 lalu label :labelID dihapus
+func MergeLabel(c *gin.Context) {
+	var from, into models.Label
+	if !findLabel(c, &from, "labelID") {
+		return
+	}
+	var input mergeLabelInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	if input.Into == from.ID {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Cannot merge a label into itself"})
+		return
+	}
+	if config.DB.Where("owner_id = ?", from.OwnerID).First(&into, input.Into).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Label tujuan tidak di temukan"})
+		return
+	}
+	var todos []models.Todo
+	err := config.DB.Transaction(func(tx *gorm.DB) (err error) {
+		if err = models.MergeLabel(tx, from.ID, into.ID); err != nil {
+			return err
+		}
+		if todos, err = labelTodos(tx, into.ID); err != nil {
+			return err
+		}
+		return touchTodos(tx, todos)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	publishTodos(todos)
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menggabungkan label", "label" : into})
+}
+
+// AddTodoLabel POST /todo/:id/labels/:labelID, editor list boleh pasang
+// label miliknya sendiri
+func AddTodoLabel(c *gin.Context) {
+	var todo models.Todo
+	var label models.Label
+	if !findTodo(c, &todo, models.ListEditor) || !findLabel(c, &label, "labelID") {
+		return
+	}
+	labelChanged(c, todo, "Berhasil memasang label", func(tx *gorm.DB) error {
+		return tx.Model(&todo).Association("Labels").Append(&label)
+	})
+}
+
+// RemoveTodoLabel DELETE /todo/:id/labels/:labelID. Label siapa pun boleh
+// dilepas editor, biar label dari anggota yang sudah keluar tidak nyangkut
+func RemoveTodoLabel(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	labelID, err := strconv.Atoi(c.Param("labelID"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Label tidak di temukan"})
+		return
+	}
+	labelChanged(c, todo, "Berhasil melepas label", func(tx *gorm.DB) error {
+		return tx.Where("todo_id = ? AND label_id = ?", todo.ID, labelID).Delete(&models.TodoLabel{}).Error
+	})
+}
+
+// labelChanged jalankan perubahan label satu todo bareng touchTodos dalam
+// satu transaksi
+func labelChanged(c *gin.Context, todo models.Todo, message string, change func(tx *gorm.DB) error) {
+	todos := []models.Todo{todo}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := change(tx); err != nil {
+			return err
+		}
+		return touchTodos(tx, todos)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	publishTodos(todos)
+	c.JSON(http.StatusOK, gin.H{"message" : message, "todo" : todos[0]})
+}
@@ -0,0 +1,356 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo/config"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findList ambil list dari param :listID, user minimal punya role need.
+// List yang tidak diikuti dianggap tidak ada
+func findList(c *gin.Context, list *models.List, need string) (string, bool) {
+	id, err := strconv.Atoi(c.Param("listID"))
+	userID := c.MustGet("userID").(uint)
+	role := ""
+	if err == nil {
+		role = models.MemberRole(config.DB, uint(id), userID)
+	}
+	if role == "" || config.DB.First(list, id).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "List not found"})
+		return "", false
+	}
+	if !models.RoleAtLeast(role, need) {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "You need " + need + " access to this list"})
+		return "", false
+	}
+	return role, true
+}
+
+type listWithRole struct {
+	models.List
+	Role string `json:"role"`
+}
+
+// GetLists GET /lists, semua list yang diikuti user beserta role-nya
+func GetLists(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+	if _, err := models.PersonalList(config.DB, userID); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	var lists []listWithRole
+	err := config.DB.Model(&models.List{}).
+		Select("lists.*, list_members.role").
+		Joins("JOIN list_members ON list_members.list_id = lists.id").
+		Where("list_members.user_id = ?", userID).
+		Order("lists.personal desc, lists.id").
+		Scan(&lists).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"lists" : lists})
+}
+
+type listInput struct {
+	Name string `json:"nama" binding:"required"`
+}
+
+// CreateList POST /lists
+func CreateList(c *gin.Context) {
+	var input listInput
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	list := models.List{Name: strings.TrimSpace(input.Name), OwnerID: c.MustGet("userID").(uint)}
+	if err := models.CreateList(config.DB, &list); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil membuat list", "list" : list})
+}
+
+// RenameList PUT /lists/:listID, khusus owner
+func RenameList(c *gin.Context) {
+	var list models.List
+	if _, ok := findList(c, &list, models.ListOwner); !ok {
+		return
+	}
+	var input listInput
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	list.Name = strings.TrimSpace(input.Name)
+	if err := config.DB.Save(&list).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update list", "list" : list})
+}
+
+// DeleteList DELETE /lists/:listID, khusus owner. Todo di dalamnya ikut
+// terhapus, list Personal tidak bisa dihapus
+func DeleteList(c *gin.Context) {
+	var list models.List
+	if _, ok := findList(c, &list, models.ListOwner); !ok {
+		return
+	}
+	if list.Personal {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "The personal list cannot be deleted"})
+		return
+	}
+	var blobs []string
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var ids []uint
+		if err := tx.Model(&models.Todo{}).Where("list_id = ?", list.ID).Pluck("id", &ids).Error; err != nil {
+			return err
+		}
+		var err error
+		if blobs, err = deleteTodos(tx, ids); err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ?", list.ID).Delete(&models.ListMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("list_id = ? AND status = ?", list.ID, models.InvitePending).Delete(&models.ListInvite{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&list).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	deleteBlobs(c.Request.Context(), blobs)
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menghapus list"})
+}
+
+type memberInfo struct {
+	UserID   uint   `json:"user_id"`
+	Username string `json:"username"`
+	Role     string `json:"role"`
+}
+
+// GetListMembers GET /lists/:listID/members
+func GetListMembers(c *gin.Context) {
+	var list models.List
+	if _, ok := findList(c, &list, models.ListViewer); !ok {
+		return
+	}
+	var members []memberInfo
+	err := config.DB.Model(&models.ListMember{}).
+		Select("list_members.user_id, users.username, list_members.role").
+		Joins("JOIN users ON users.id = list_members.user_id").
+		Where("list_members.list_id = ?", list.ID).
+		Order("list_members.id").
+		Scan(&members).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"members" : members})
+}
+
+type memberRoleInput struct {
+	Role string `json:"role" binding:"required"`
+}
+
+// owners jumlah owner list, list tidak boleh sampai tanpa owner
+func owners(listID uint) int64 {
+	var n int64
+	config.DB.Model(&models.ListMember{}).Where("list_id = ? AND role = ?", listID, models.ListOwner).Count(&n)
+	return n
+}
+
+// UpdateListMember PUT /lists/:listID/members/:userID, ganti role (owner)
+func UpdateListMember(c *gin.Context) {
+	var list models.List
+	if _, ok := findList(c, &list, models.ListOwner); !ok {
+		return
+	}
+	var input memberRoleInput
+	if err := c.ShouldBindJSON(&input); err != nil || !models.ValidListRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Role must be viewer, editor or owner"})
+		return
+	}
+	memberID, _ := strconv.Atoi(c.Param("userID"))
+	var member models.ListMember
+	if err := config.DB.Where("list_id = ? AND user_id = ?", list.ID, memberID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Member not found"})
+		return
+	}
+	if member.Role == models.ListOwner && input.Role != models.ListOwner && owners(list.ID) == 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "A list needs at least one owner"})
+		return
+	}
+	member.Role = input.Role
+	if err := config.DB.Save(&member).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update member", "member" : member})
+}
+
+// RemoveListMember DELETE /lists/:listID/members/:userID. Owner bisa
+// mengeluarkan siapa saja, anggota lain cuma bisa keluar sendiri
+func RemoveListMember(c *gin.Context) {
+	var list models.List
+	role, ok := findList(c, &list, models.ListViewer)
+	if !ok {
+		return
+	}
+	memberID, _ := strconv.Atoi(c.Param("userID"))
+	userID := c.MustGet("userID").(uint)
+	if uint(memberID) != userID && role != models.ListOwner {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "You need owner access to this list"})
+		return
+	}
+	var member models.ListMember
+	if err := config.DB.Where("list_id = ? AND user_id = ?", list.ID, memberID).First(&member).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Member not found"})
+		return
+	}
+	if list.Personal && member.UserID == list.OwnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "You cannot leave your personal list"})
+		return
+	}
+	if member.Role == models.ListOwner && owners(list.ID) == 1 {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "A list needs at least one owner"})
+		return
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Delete(&member).Error; err != nil {
+			return err
+		}
+		// todo yang di-assign ke dia dilepas
+		return tx.Model(&models.Todo{}).Where("list_id = ? AND assignee_id = ?", list.ID, member.UserID).Update("assignee_id", nil).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil mengeluarkan member"})
+}
+
+type inviteInput struct {
+	Username string `json:"username" binding:"required"`
+	Role     string `json:"role" binding:"required"`
+}
+
+// InviteToList POST /lists/:listID/invites, owner mengundang user lain
+func InviteToList(c *gin.Context) {
+	var list models.List
+	if _, ok := findList(c, &list, models.ListOwner); !ok {
+		return
+	}
+	var input inviteInput
+	if err := c.ShouldBindJSON(&input); err != nil || !models.ValidListRole(input.Role) {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input, role must be viewer, editor or owner"})
+		return
+	}
+	var invitee models.User
+	if err := config.DB.Where("username = ?", input.Username).First(&invitee).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "User not found"})
+		return
+	}
+	if models.MemberRole(config.DB, list.ID, invitee.ID) != "" {
+		c.JSON(http.StatusConflict, gin.H{"error" : "User is already a member"})
+		return
+	}
+
+	// undangan pending ke user yang sama cukup diperbarui role-nya
+	var invite models.ListInvite
+	err := config.DB.Where("list_id = ? AND invitee_id = ? AND status = ?", list.ID, invitee.ID, models.InvitePending).First(&invite).Error
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	invite.ListID = list.ID
+	invite.InviteeID = invitee.ID
+	invite.InviterID = c.MustGet("userID").(uint)
+	invite.Role = input.Role
+	invite.Status = models.InvitePending
+	if err := config.DB.Save(&invite).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Undangan terkirim", "invite" : invite})
+}
+
+type inviteInfo struct {
+	models.ListInvite
+	ListName        string `json:"list_nama"`
+	InviterUsername string `json:"inviter_username"`
+}
+
+// GetMyInvites GET /invites, undangan pending untuk user yang login
+func GetMyInvites(c *gin.Context) {
+	var invites []inviteInfo
+	err := config.DB.Model(&models.ListInvite{}).
+		Select("list_invites.*, lists.name AS list_name, users.username AS inviter_username").
+		Joins("JOIN lists ON lists.id = list_invites.list_id AND lists.deleted_at IS NULL").
+		Joins("JOIN users ON users.id = list_invites.inviter_id").
+		Where("list_invites.invitee_id = ? AND list_invites.status = ?", c.MustGet("userID").(uint), models.InvitePending).
+		Order("list_invites.id").
+		Scan(&invites).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"invites" : invites})
+}
+
+func findMyInvite(c *gin.Context, invite *models.ListInvite) bool {
+	id, err := strconv.Atoi(c.Param("inviteID"))
+	userID := c.MustGet("userID").(uint)
+	if err != nil || config.DB.Where("invitee_id = ? AND status = ?", userID, models.InvitePending).First(invite, id).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Invite not found"})
+		return false
+	}
+	return true
+}
+
+// AcceptInvite POST /invites/:inviteID/accept
+func AcceptInvite(c *gin.Context) {
+	var invite models.ListInvite
+	if !findMyInvite(c, &invite) {
+		return
+	}
+	var list models.List
+	if err := config.DB.First(&list, invite.ListID).Error; err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "List no longer exists"})
+		return
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&invite).Update("status", models.InviteAccepted).Error; err != nil {
+			return err
+		}
+		return tx.Create(&models.ListMember{ListID: invite.ListID, UserID: invite.InviteeID, Role: invite.Role}).Error
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil bergabung ke list", "list" : list, "role" : invite.Role})
+}
+
+// DeclineInvite POST /invites/:inviteID/decline
+func DeclineInvite(c *gin.Context) {
+	var invite models.ListInvite
+	if !findMyInvite(c, &invite) {
+		return
+	}
+	if err := config.DB.Model(&invite).Update("status", models.InviteDeclined).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Undangan ditolak"})
+}
@@ -0,0 +1,70 @@
+package controller
+
+import (
+	"net/http"
+	"strings"
+	"time"
+	"todo/models"
+	"todo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+type parseInput struct {
+	Text   string `json:"text" binding:"required"`
+	Create bool   `json:"create"`
+	ListID uint   `json:"list_id"`
+}
+
+// ParseTodo POST /todo/parse body {"text": "bayar listrik jumat jam 5 sore, urgent"}.
+// Balikin usulan todo; kalau "create": true langsung disimpan. AI dipakai
+// kalau nyala, kalau mati atau gagal pakai parser aturan
+func ParseTodo(c *gin.Context) {
+	var input parseInput
+	if err := c.ShouldBindJSON(&input); err != nil || strings.TrimSpace(input.Text) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	userID := c.MustGet("userID").(uint)
+	now := time.Now()
+	listID, ok := resolveList(c, input.ListID)
+	if !ok {
+		return
+	}
+
+	source := "rules"
+	parsed := utils.ParseTodoRules(input.Text, now)
+	if utils.AIEnabled() {
+		if fromAI, err := utils.ParseTodoAI(c.Request.Context(), input.Text, now); err == nil {
+			parsed, source = fromAI, "ai"
+		}
+	}
+
+	todo := models.Todo{
+		Title:     parsed.Title,
+		Desc:      parsed.Desc,
+		Status:    models.Status(parsed.Status),
+		Priority:  models.Priority(parsed.Priority),
+		DueAt:     parsed.DueAt,
+		UserID:    userID,
+		ListID:    listID,
+		Timestamp: now,
+	}
+	// jawaban AI yang aneh jangan sampai masuk DB
+	if !models.ValidStatus(todo.Status) {
+		todo.Status = models.Pending
+	}
+	if !models.ValidPriority(todo.Priority) {
+		todo.Priority = models.PriorityNormal
+	}
+
+	if !input.Create {
+		c.JSON(http.StatusOK, gin.H{"todo" : todo, "source" : source, "created" : false})
+		return
+	}
+	if err := createTodo(&todo); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil menambah todo", "todo" : todo, "source" : source, "created" : true})
+}
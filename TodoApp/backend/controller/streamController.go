@@ -0,0 +1,75 @@
+package controller
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+	"todo/events"
+
+	"github.com/gin-gonic/gin"
+)
+
+const heartbeatInterval = 15 * time.Second
+
+// StreamTodos GET /todos/stream, Server-Sent Events untuk perubahan todo
+// milik user. Resume pakai header Last-Event-ID (otomatis dari EventSource)
+// atau ?last_event_id=. Kalau event yang terlewat sudah tidak ada, event
+// "reset" dikirim dan client sebaiknya fetch ulang GET /todos/my
+func StreamTodos(c *gin.Context) {
+	userID := c.MustGet("userID").(uint)
+
+	lastRaw := c.GetHeader("Last-Event-ID")
+	if lastRaw == "" {
+		lastRaw = c.Query("last_event_id")
+	}
+	lastID, _ := strconv.ParseUint(lastRaw, 10, 64)
+
+	ch, missed, complete, cancel := events.Default.Subscribe(userID, lastID)
+	defer cancel()
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprint(w, "retry: 3000\n\n")
+	if !complete {
+		fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+	}
+	for _, e := range missed {
+		writeEvent(w, e)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(heartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case e, ok := <-ch:
+			if !ok {
+				// diputus bus karena ketinggalan, client reconnect sendiri
+				return
+			}
+			writeEvent(w, e)
+			w.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		}
+	}
+}
+
+func writeEvent(w io.Writer, e events.Event) {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+}
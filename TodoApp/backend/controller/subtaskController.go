@@ -0,0 +1,160 @@
+package controller
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"todo/config"
+	"todo/models"
+	"todo/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// findSubtask ambil subtask :subID di bawah todo :id, user minimal editor
+func findSubtask(c *gin.Context, todo *models.Todo, sub *models.Subtask) bool {
+	if !findTodo(c, todo, models.ListEditor) {
+		return false
+	}
+	subID, err := strconv.Atoi(c.Param("subID"))
+	if err != nil || config.DB.Where("todo_id = ?", todo.ID).First(sub, subID).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Subtask tidak di temukan"})
+		return false
+	}
+	return true
+}
+
+// GetSubtasks GET /todo/:id/subtasks
+func GetSubtasks(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListViewer) {
+		return
+	}
+	var subtasks []models.Subtask
+	if err := config.DB.Where("todo_id = ?", todo.ID).Order("position, id").Find(&subtasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"subtasks" : subtasks})
+}
+
+// SuggestSubtasks POST /todo/:id/subtasks/suggest, AI mecah todo jadi 3-8
+// langkah. Hasilnya TIDAK disimpan: user boleh edit atau buang dulu, lalu
+// kirim yang dipilih ke POST /todo/:id/subtasks
+func SuggestSubtasks(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	steps, err := utils.BreakdownTodo(c.Request.Context(), todo.Title, todo.Desc)
+	if errors.Is(err, utils.ErrAIDisabled) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error" : err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadGateway, gin.H{"error" : "AI gagal memecah todo: " + err.Error()})
+		return
+	}
+	suggestions := make([]gin.H, 0, len(steps))
+	for _, step := range steps {
+		suggestions = append(suggestions, gin.H{"judul" : step})
+	}
+	c.JSON(http.StatusOK, gin.H{"todo_id" : todo.ID, "suggestions" : suggestions})
+}
+
+type subtaskInput struct {
+	Title string `json:"judul"`
+}
+
+type createSubtasks struct {
+	Subtasks []subtaskInput `json:"subtasks" binding:"required"`
+}
+
+// CreateSubtasks POST /todo/:id/subtasks body {"subtasks": [{"judul": "..."}]},
+// dipakai buat nyimpen saran AI yang diterima atau subtask yang ditulis sendiri
+func CreateSubtasks(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	var input createSubtasks
+	if err := c.ShouldBindJSON(&input); err != nil || len(input.Subtasks) == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+
+	var last models.Subtask
+	position := 0
+	if config.DB.Where("todo_id = ?", todo.ID).Order("position desc").First(&last).Error == nil {
+		position = last.Position + 1
+	}
+
+	subtasks := make([]models.Subtask, 0, len(input.Subtasks))
+	for _, in := range input.Subtasks {
+		title := strings.TrimSpace(in.Title)
+		if title == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : "Subtask title cannot be empty"})
+			return
+		}
+		subtasks = append(subtasks, models.Subtask{TodoID: todo.ID, Title: title, Status: models.Pending, Position: position})
+		position++
+	}
+	if err := config.DB.Create(&subtasks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil menambah subtask", "subtasks" : subtasks})
+}
+
+type updateSubtask struct {
+	Title  *string        `json:"judul"`
+	Status *models.Status `json:"status"`
+}
+
+// UpdateSubtask PUT /todo/:id/subtasks/:subID, ganti judul dan/atau status
+func UpdateSubtask(c *gin.Context) {
+	var todo models.Todo
+	var sub models.Subtask
+	if !findSubtask(c, &todo, &sub) {
+		return
+	}
+	var input updateSubtask
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	if input.Title != nil {
+		if strings.TrimSpace(*input.Title) == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : "Subtask title cannot be empty"})
+			return
+		}
+		sub.Title = strings.TrimSpace(*input.Title)
+	}
+	if input.Status != nil {
+		if !models.ValidStatus(*input.Status) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be Pending, InProgress, Success, or Failed"})
+			return
+		}
+		sub.Status = *input.Status
+	}
+	if err := config.DB.Save(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update subtask", "subtask" : sub})
+}
+
+// DeleteSubtask DELETE /todo/:id/subtasks/:subID
+func DeleteSubtask(c *gin.Context) {
+	var todo models.Todo
+	var sub models.Subtask
+	if !findSubtask(c, &todo, &sub) {
+		return
+	}
+	if err := config.DB.Delete(&sub).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menghapus subtask"})
+}
@@ -1,16 +1,99 @@
 package controller
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
 	"net/http"
+	"slices"
 	"strconv"
 	"time"
 	"todo/config"
+	"todo/events"
 	"todo/models"
+	"todo/storage"
 	"todo/utils"
+	"todo/worker"
 
 	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"gorm.io/gorm"
 )
 
+// findTodo ambil todo dari param :id yang list-nya diikuti user dengan role
+// minimal need. Todo di list lain dianggap tidak ada (404) biar id-nya tidak
+// bisa ditebak, anggota yang haknya kurang dapat 403
+func findTodo(c *gin.Context, todo *models.Todo, need string) bool {
+	id, err := strconv.Atoi(c.Param("id"))
+	userID := c.MustGet("userID").(uint)
+	if err != nil || config.DB.Where("list_id IN (?)", models.MemberListIDs(config.DB, userID, models.ListViewer)).First(todo, id).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Todo tidak di temukan"})
+		return false
+	}
+	if need != models.ListViewer && !models.RoleAtLeast(models.MemberRole(config.DB, todo.ListID, userID), need) {
+		c.JSON(http.StatusForbidden, gin.H{"error" : "You need " + need + " access to this list"})
+		return false
+	}
+	return true
+}
+
+// resolveList list tujuan todo baru: 0 berarti list Personal user, selain
+// itu user harus minimal editor di list tersebut
+func resolveList(c *gin.Context, listID uint) (uint, bool) {
+	userID := c.MustGet("userID").(uint)
+	if listID == 0 {
+		list, err := models.PersonalList(config.DB, userID)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+			return 0, false
+		}
+		return list.ID, true
+	}
+	switch role := models.MemberRole(config.DB, listID, userID); {
+	case role == "":
+		c.JSON(http.StatusNotFound, gin.H{"error" : "List not found"})
+		return 0, false
+	case !models.RoleAtLeast(role, models.ListEditor):
+		c.JSON(http.StatusForbidden, gin.H{"error" : "You need editor access to this list"})
+		return 0, false
+	}
+	return listID, true
+}
+
+// checkAssignee assignee harus anggota list todo-nya
+func checkAssignee(c *gin.Context, listID uint, assigneeID *uint) bool {
+	if assigneeID == nil || models.MemberRole(config.DB, listID, *assigneeID) != "" {
+		return true
+	}
+	c.JSON(http.StatusBadRequest, gin.H{"error" : "Assignee must be a member of the list"})
+	return false
+}
+
+// publishTodo kirim event ke semua anggota list todo
+func publishTodo(eventType string, todo models.Todo) {
+	events.Publish(eventType, models.TodoAudience(config.DB, todo), todo.ID, todo)
+}
+
+// outboxTodo catat kiriman webhook untuk event todo di tx yang sama dengan
+// perubahannya. Event yang sama dipublish setelah commit, yang sekaligus
+// bangunin worker webhook
+func outboxTodo(tx *gorm.DB, eventType string, todo models.Todo, data interface{}) error {
+	return worker.EnqueueEvent(tx, eventType, models.TodoAudience(tx, todo), todo.ID, data)
+}
+
+// withoutIDs isi ids yang tidak ada di exclude
+func withoutIDs(ids, exclude []uint) []uint {
+	var out []uint
+	for _, id := range ids {
+		if !slices.Contains(exclude, id) {
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+// GetAllTodo semua todo dari semua user, route-nya dijaga RequireRole admin
 func GetAllTodo(c *gin.Context) {
 	var todo []models.Todo
 	if err := config.DB.Find(&todo).Error; err != nil {
@@ -20,15 +103,51 @@ func GetAllTodo(c *gin.Context) {
 	c.JSON(http.StatusOK, todo)
 }
 
+// GetMyTodos todo di semua list yang diikuti user, bisa difilter, diurutkan dan
+// dipaginasi (lihat todoQuery)
 func GetMyTodos(c *gin.Context) {
-	var todos []models.Todo
 	userID := c.MustGet("userID").(uint)
-	if err := config.DB.Where("user_id", userID).Find(&todos).Error; err != nil {
+	q, err := parseTodoQuery(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+		return
+	}
+
+	base := config.DB.Model(&models.Todo{}).Where("list_id IN (?)", models.MemberListIDs(config.DB, userID, models.ListViewer))
+	filtered := q.filter(base, userID)
+
+	var total int64
+	if err := filtered.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+
+	paged, err := q.paginate(filtered.Session(&gorm.Session{}))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+		return
+	}
+	var todos []models.Todo
+	if err := paged.Preload("Labels").Find(&todos).Error; err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
 		return
 	}
-	c.JSON(http.StatusOK, gin.H{"todos" : todos})
+
+	// ambil satu lebih dari limit buat tahu masih ada halaman berikutnya
+	hasMore := len(todos) > q.limit
+	if hasMore {
+		todos = todos[:q.limit]
+	}
+	meta := gin.H{"total" : total, "limit" : q.limit, "has_more" : hasMore, "next_cursor" : nil}
+	if q.cursor == nil {
+		meta["page"] = q.page
+	}
+	if hasMore {
+		meta["next_cursor"] = q.cursorFor(todos[len(todos)-1])
+	}
+	c.JSON(http.StatusOK, gin.H{"todos" : todos, "meta" : meta})
 }
+
 func CreateTodo(c *gin.Context) {
 	var input models.Todo
 	if err := c.ShouldBindJSON(&input); err != nil {
@@ -43,27 +162,33 @@ func CreateTodo(c *gin.Context) {
 		return
 	}
 
-	// Kalau deskripsi kosong → generate pake AI
-	desc := input.Desc
-	if desc == "" {
-		generated, err := utils.GenerateDescription(input.Title)
-		if err != nil {
-			// kalau AI gagal, fallback ke default
-			desc = "Tidak ada deskripsi (AI gagal generate)"
-		} else {
-			desc = generated
-		}
+	if input.Status != "" && !models.ValidStatus(input.Status) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be Pending, InProgress, Success, or Failed"})
+		return
+	}
+	if input.Priority != "" && !models.ValidPriority(input.Priority) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be low, normal, high, or urgent"})
+		return
+	}
+
+	listID, ok := resolveList(c, input.ListID)
+	if !ok || !checkAssignee(c, listID, input.AssigneeID) {
+		return
 	}
 
 	todo := models.Todo{
 		Title:  input.Title,
-		Desc:   desc,
+		Desc:   input.Desc,
 		Status: input.Status,
+		Priority: input.Priority,
+		DueAt: input.DueAt,
+		ReminderMinutes: input.ReminderMinutes,
 		UserID: userID,
+		ListID: listID,
+		AssigneeID: input.AssigneeID,
 		Timestamp : time.Now(),
 	}
-
-	if err := config.DB.Create(&todo).Error; err != nil {
+	if err := createTodo(&todo); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -74,14 +199,98 @@ func CreateTodo(c *gin.Context) {
 	})
 }
 
+// createTodo simpan todo baru. Kalau deskripsi kosong → dibuat AI di
+// background, todo langsung disimpan tanpa nunggu AI
+func createTodo(todo *models.Todo) error {
+	if todo.Status == "" {
+		todo.Status = models.Pending
+	}
+	setDescription(todo, todo.Desc)
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(todo).Error; err != nil {
+			return err
+		}
+		if err := models.RecordStatus(tx, todo.ID, "", todo.Status, &todo.UserID, ""); err != nil {
+			return err
+		}
+		if err := models.SyncReminders(tx, todo, todo.ReminderMinutes); err != nil {
+			return err
+		}
+		return outboxTodo(tx, events.TodoCreated, *todo, *todo)
+	})
+	if err != nil {
+		return err
+	}
+	if todo.DescStatus == models.DescPending {
+		worker.EnqueueDescription(todo.ID)
+	}
+	publishTodo(events.TodoCreated, *todo)
+	return nil
+}
+
+// setDescription deskripsi dari user disimpan apa adanya (manual), kalau
+// kosong ditandai pending buat worker AI. Tanpa AI langsung failed biar
+// client tidak nunggu selamanya
+func setDescription(todo *models.Todo, desc string) {
+	todo.Desc = desc
+	todo.DescError = ""
+	switch {
+	case desc != "":
+		todo.DescStatus = models.DescManual
+	case utils.AIEnabled():
+		todo.DescStatus = models.DescPending
+	default:
+		todo.DescStatus = models.DescFailed
+		todo.DescError = utils.ErrAIDisabled.Error()
+	}
+}
+
+// GetTodo GET /todo/:id, dipakai juga buat polling deskripsi_status
+func GetTodo(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListViewer) {
+		return
+	}
+	config.DB.Where("todo_id = ?", todo.ID).Order("position, id").Find(&todo.Subtasks)
+	config.DB.Model(&todo).Association("Labels").Find(&todo.Labels)
+	models.LoadReminderMinutes(config.DB, &todo)
+	c.JSON(http.StatusOK, gin.H{"todo" : todo})
+}
+
+// RegenerateDescription POST /todo/:id/description, minta AI bikin ulang
+// deskripsi. Hasilnya dicek lewat GET /todo/:id
+func RegenerateDescription(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	if !utils.AIEnabled() {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error" : utils.ErrAIDisabled.Error()})
+		return
+	}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&todo).Updates(map[string]interface{}{"desc_status": models.DescPending, "desc_error": ""}).Error; err != nil {
+			return err
+		}
+		todo.DescStatus, todo.DescError = models.DescPending, ""
+		return outboxTodo(tx, events.TodoUpdated, todo, todo)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	worker.EnqueueDescription(todo.ID)
+	publishTodo(events.TodoUpdated, todo)
+	c.JSON(http.StatusAccepted, gin.H{"message" : "Deskripsi sedang dibuat", "todo" : todo})
+}
+
 type updateStatus struct {
 	Status models.Status `json:"status" binding:"required"`
+	Reason string `json:"alasan"`
 }
 func UpdateStatus(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
 	var todo models.Todo
-	if err := config.DB.First(&todo, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error" : "Todo tidak di temukan"})
+	if !findTodo(c, &todo, models.ListEditor) {
 		return
 	}
 
@@ -91,68 +300,247 @@ func UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if input.Status != models.Pending && input.Status != models.InProgress && input.Status != models.Failed && input.Status != models.Success {
+	if !models.ValidStatus(input.Status) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Status must be Pending, InProgress, Success, or Failed"})
 		return
 	}
-	todo.Status = input.Status
-	if err := config.DB.Save(&todo).Error; err != nil {
+	userID := c.MustGet("userID").(uint)
+	from := todo.Status
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := models.ChangeStatus(tx, &todo, input.Status, &userID, input.Reason); err != nil {
+			return err
+		}
+		if err := outboxTodo(tx, events.TodoUpdated, todo, todo); err != nil {
+			return err
+		}
+		return outboxTodo(tx, events.TodoStatus, todo, events.StatusChange{From: from, To: todo.Status, Todo: todo})
+	})
+	switch {
+	case errors.Is(err, models.ErrIllegalTransition):
+		c.JSON(http.StatusUnprocessableEntity, gin.H{
+			"error" : fmt.Sprintf("Status tidak bisa pindah dari %s ke %s", todo.Status, input.Status),
+			"allowed" : models.AllowedTransitions(todo.Status),
+		})
+		return
+	case errors.Is(err, models.ErrStatusChanged):
+		c.JSON(http.StatusConflict, gin.H{"error" : err.Error()})
+		return
+	case err != nil:
 		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
 		return
 	}
+	publishTodo(events.TodoUpdated, todo)
+	events.Publish(events.TodoStatus, models.TodoAudience(config.DB, todo), todo.ID, events.StatusChange{From: from, To: todo.Status, Todo: todo})
 	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update status", "title" : todo.Title, "status" : todo.Status})
 }
 
-func UpdateTodo (c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
+// GetStatusHistory GET /todo/:id/history, riwayat status plus lead/cycle time
+func GetStatusHistory(c *gin.Context) {
 	var todo models.Todo
-	if err := config.DB.First(&todo, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error" : "Todo not found"})
+	if !findTodo(c, &todo, models.ListViewer) {
 		return
 	}
-	var input models.Todo
+	var history []models.StatusHistory
+	if err := config.DB.Where("todo_id = ?", todo.ID).Order("created_at, id").Find(&history).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+
+	ct := models.ComputeCycleTimes(todo.CreatedAt, todo.Status, history, time.Now())
+	times := gin.H{}
+	for status, d := range ct.TimeInStatus {
+		times[string(status)] = durationJSON(d)
+	}
+	cycle := gin.H{"time_in_status" : times, "lead_time" : nil, "cycle_time" : nil}
+	if ct.LeadTime != nil {
+		cycle["lead_time"] = durationJSON(*ct.LeadTime)
+	}
+	if ct.CycleTime != nil {
+		cycle["cycle_time"] = durationJSON(*ct.CycleTime)
+	}
+	c.JSON(http.StatusOK, gin.H{"history" : history, "cycle_times" : cycle})
+}
+
+func durationJSON(d time.Duration) gin.H {
+	return gin.H{"seconds" : int64(d.Seconds()), "text" : d.Round(time.Second).String()}
+}
+
+type updateAssignee struct {
+	AssigneeID *uint `json:"assignee_id"`
+}
+
+// AssignTodo PUT /todo/:id/assignee body {"assignee_id": 5} atau null buat
+// melepas assignee
+func AssignTodo(c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	var input updateAssignee
 	if err := c.ShouldBindJSON(&input); err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
 		return
 	}
-	if err := config.DB.Updates(&todo).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+	if !checkAssignee(c, todo.ListID, input.AssigneeID) {
 		return
 	}
-	desc := input.Desc
-	if desc == "" {
-		generated, err := utils.GenerateDescription(input.Title)
-		if err != nil {
-			// kalau AI gagal, fallback ke default
-			desc = "Tidak ada deskripsi (AI gagal generate)"
-		} else {
-			desc = generated
+	todo.AssigneeID = input.AssigneeID
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Model(&todo).Update("assignee_id", input.AssigneeID).Error; err != nil {
+			return err
 		}
+		return outboxTodo(tx, events.TodoUpdated, todo, todo)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
 	}
-	if input.Timestamp.IsZero() {
-    todo.Timestamp = time.Now()
-} else {
-    todo.Timestamp = input.Timestamp
+	publishTodo(events.TodoUpdated, todo)
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update assignee", "todo" : todo})
 }
+
+func UpdateTodo (c *gin.Context) {
+	var todo models.Todo
+	if !findTodo(c, &todo, models.ListEditor) {
+		return
+	}
+	var input models.Todo
+	if err := c.ShouldBindBodyWith(&input, binding.JSON); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	// models.Todo tidak bisa bedain deskripsi kosong dan tidak dikirim
+	var desc struct {
+		Desc *string `json:"deskripsi"`
+	}
+	c.ShouldBindBodyWith(&desc, binding.JSON)
+	if input.Priority != "" {
+		if !models.ValidPriority(input.Priority) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Priority must be low, normal, high, or urgent"})
+			return
+		}
+		todo.Priority = input.Priority
+	}
+	if input.DueAt != nil {
+		todo.DueAt = input.DueAt
+	}
+	// pindah list: harus editor juga di list tujuan, anggota list lama yang
+	// bukan anggota list baru dapat todo.moved sebelum todo hilang dari list
+	// mereka
+	oldAudience := models.TodoAudience(config.DB, todo)
+	oldListID := todo.ListID
+	if input.ListID != 0 && input.ListID != todo.ListID {
+		listID, ok := resolveList(c, input.ListID)
+		if !ok {
+			return
+		}
+		todo.ListID = listID
+		if todo.AssigneeID != nil && models.MemberRole(config.DB, listID, *todo.AssigneeID) == "" {
+			todo.AssigneeID = nil
+		}
+	}
+	if input.AssigneeID != nil {
+		if !checkAssignee(c, todo.ListID, input.AssigneeID) {
+			return
+		}
+		todo.AssigneeID = input.AssigneeID
+	}
 	todo.Title = input.Title
-	todo.Desc = desc
-	if err := config.DB.Save(&todo).Error; err != nil {
+	// deskripsi tidak dikirim → deskripsi dan statusnya tetap, string kosong
+	// → dibuat ulang AI
+	if desc.Desc != nil {
+		setDescription(&todo, *desc.Desc)
+	}
+	var removed []uint
+	moved := events.Moved{FromListID: oldListID, ToListID: todo.ListID}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&todo).Error; err != nil {
+			return err
+		}
+		// pengingat_menit tidak dikirim → offset lama dihitung ulang dari tenggat baru
+		if err := models.SyncReminders(tx, &todo, input.ReminderMinutes); err != nil {
+			return err
+		}
+		if err := outboxTodo(tx, events.TodoUpdated, todo, todo); err != nil {
+			return err
+		}
+		if todo.ListID != oldListID {
+			removed = withoutIDs(oldAudience, models.TodoAudience(tx, todo))
+			return worker.EnqueueEvent(tx, events.TodoMoved, removed, todo.ID, moved)
+		}
+		return nil
+	})
+	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
-		return 
+		return
+	}
+	if desc.Desc != nil && todo.DescStatus == models.DescPending {
+		worker.EnqueueDescription(todo.ID)
+	}
+	publishTodo(events.TodoUpdated, todo)
+	if len(removed) > 0 {
+		events.Publish(events.TodoMoved, removed, todo.ID, moved)
 	}
 	c.JSON(http.StatusOK, gin.H{"message" : "Completed update todo", "todo" : todo})
 }
 
 func DeleteTodo(c *gin.Context) {
-	id, _ := strconv.Atoi(c.Param("id"))
 	var todo models.Todo
-	if err := config.DB.First(&todo, id).Error; err != nil {
-		c.JSON(http.StatusNotFound, gin.H{"error" : "Todo tidak di temukan"})
+	if !findTodo(c, &todo, models.ListEditor) {
 		return
 	}
-	if err := config.DB.Delete(&todo).Error; err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error})
+	var blobs []string
+	audience := models.TodoAudience(config.DB, todo)
+	err := config.DB.Transaction(func(tx *gorm.DB) (err error) {
+		if blobs, err = deleteTodos(tx, []uint{todo.ID}); err != nil {
+			return err
+		}
+		return worker.EnqueueEvent(tx, events.TodoDeleted, audience, todo.ID, nil)
+	})
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
 		return
 	}
+	deleteBlobs(c.Request.Context(), blobs)
+	events.Publish(events.TodoDeleted, audience, todo.ID, nil)
 	c.JSON(http.StatusOK, gin.H{"error" : "Berhasil menghapus catatan"})
-}
\ No newline at end of file
+}
+
+// deleteTodos hapus todo beserta subtask, komentar, lampiran, pengingat dan
+// tempelan labelnya. Dipakai DeleteTodo dan DeleteList biar tidak ada sisa.
+// Lampiran dihapus permanen seperti DeleteAttachment; key file-nya
+// dikembalikan supaya dihapus dari storage setelah transaksi commit
+func deleteTodos(tx *gorm.DB, ids []uint) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	var blobs []string
+	if err := tx.Model(&models.Attachment{}).Where("todo_id IN ?", ids).Pluck("storage_key", &blobs).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Unscoped().Where("todo_id IN ?", ids).Delete(&models.Attachment{}).Error; err != nil {
+		return nil, err
+	}
+	for _, model := range []interface{}{&models.Subtask{}, &models.Comment{}} {
+		if err := tx.Where("todo_id IN ?", ids).Delete(model).Error; err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Unscoped().Where("todo_id IN ?", ids).Delete(&models.Reminder{}).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.Where("todo_id IN ?", ids).Delete(&models.TodoLabel{}).Error; err != nil {
+		return nil, err
+	}
+	return blobs, tx.Delete(&models.Todo{}, ids).Error
+}
+
+// deleteBlobs hapus file lampiran dari storage. Gagal di sini tidak
+// membatalkan penghapusan todo, cukup dicatat di log
+func deleteBlobs(ctx context.Context, keys []string) {
+	for _, key := range keys {
+		if err := storage.Default.Delete(ctx, key); err != nil && !errors.Is(err, storage.ErrNotFound) {
+			log.Printf("gagal hapus lampiran %s: %v", key, err)
+		}
+	}
+}
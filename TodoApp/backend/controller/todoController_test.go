@@ -0,0 +1,91 @@
+package controller
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"todo/config"
+	"todo/migrations"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// openTestDB SQLite baru di folder sementara, sudah dimigrate
+func openTestDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := models.SetupJoinTables(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+// useTestDB ganti config.DB dengan database sementara
+func useTestDB(t *testing.T) {
+	t.Helper()
+	old := config.DB
+	config.DB = openTestDB(t)
+	t.Cleanup(func() { config.DB = old })
+}
+
+// call jalankan handler sebagai userID dengan param :id dan body JSON
+func call(handler gin.HandlerFunc, userID uint, id uint, body string) *httptest.ResponseRecorder {
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/", strings.NewReader(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(int(id))}}
+	c.Set("userID", userID)
+	handler(c)
+	return w
+}
+
+func TestUpdateTodoKeepsDescription(t *testing.T) {
+	useTestDB(t)
+	list, err := models.PersonalList(config.DB, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	todo := models.Todo{Title: "laporan", Desc: "tulisan AI", DescStatus: models.DescGenerated, UserID: 1, ListID: list.ID}
+	config.DB.Create(&todo)
+	reload := func() models.Todo {
+		var fresh models.Todo
+		config.DB.First(&fresh, todo.ID)
+		return fresh
+	}
+
+	// deskripsi tidak dikirim: tetap
+	if w := call(UpdateTodo, 1, todo.ID, `{"judul": "laporan bulanan"}`); w.Code != http.StatusOK {
+		t.Fatalf("update = %d %s", w.Code, w.Body)
+	}
+	if got := reload(); got.Title != "laporan bulanan" || got.Desc != "tulisan AI" || got.DescStatus != models.DescGenerated {
+		t.Fatalf("title-only update changed the description: %+v", got)
+	}
+
+	// deskripsi diisi: manual
+	call(UpdateTodo, 1, todo.ID, `{"judul": "laporan bulanan", "deskripsi": "tulis sendiri"}`)
+	if got := reload(); got.Desc != "tulis sendiri" || got.DescStatus != models.DescManual {
+		t.Fatalf("manual description not saved: %+v", got)
+	}
+
+	// dikosongkan: dibuat ulang, tanpa AI langsung failed
+	call(UpdateTodo, 1, todo.ID, `{"judul": "laporan bulanan", "deskripsi": ""}`)
+	if got := reload(); got.Desc != "" || got.DescStatus != models.DescFailed {
+		t.Fatalf("cleared description should be regenerated: %+v", got)
+	}
+}
@@ -0,0 +1,320 @@
+package controller
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLimit = 50
+	maxLimit     = 200
+)
+
+// sortColumns nama sort di query string -> kolom di tabel todos
+var sortColumns = map[string]string{
+	"created": "created_at",
+	"updated": "updated_at",
+	"date":    "timestamp",
+	"title":   "title",
+	"status":  "status",
+}
+
+// todoQuery parameter GET /todos/my:
+//
+//	status=Pending,InProgress (boleh diulang)
+//	created_from, created_to, updated_from, updated_to (YYYY-MM-DD atau RFC3339)
+//	q=teks (cari di judul dan deskripsi)
+//	list_id=N, assignee=me|none|<user id>
+//	labels=1,2 (punya salah satu), labels_all=1,2 (punya semua),
+//	labels_none=1,2 (tidak punya satupun), isinya id label
+//	sort=created|updated|date|title|status, order=asc|desc
+//	limit=50 plus page=N atau cursor=<next_cursor dari response sebelumnya>
+type todoQuery struct {
+	statuses               []models.Status
+	createdFrom, createdTo *time.Time
+	updatedFrom, updatedTo *time.Time
+	text                   string
+	listID                 uint
+	assignee               string
+	labelsAny, labelsAll   []uint
+	labelsNone             []uint
+	sort                   string
+	desc                   bool
+	limit                  int
+	page                   int
+	cursor                 *todoCursor
+}
+
+// todoCursor posisi terakhir di urutan sort, dikirim ke client sebagai base64
+type todoCursor struct {
+	Value string `json:"v"`
+	ID    uint   `json:"id"`
+}
+
+func parseTodoQuery(c *gin.Context) (todoQuery, error) {
+	q := todoQuery{sort: "created", desc: true, limit: defaultLimit, page: 1}
+
+	for _, raw := range c.QueryArray("status") {
+		for _, s := range strings.Split(raw, ",") {
+			s = strings.TrimSpace(s)
+			if s == "" {
+				continue
+			}
+			status := models.Status(s)
+			if !models.ValidStatus(status) {
+				return q, fmt.Errorf("invalid status %q", s)
+			}
+			q.statuses = append(q.statuses, status)
+		}
+	}
+
+	var err error
+	dates := []struct {
+		name  string
+		dst   **time.Time
+		endOf bool
+	}{
+		{"created_from", &q.createdFrom, false},
+		{"created_to", &q.createdTo, true},
+		{"updated_from", &q.updatedFrom, false},
+		{"updated_to", &q.updatedTo, true},
+	}
+	for _, d := range dates {
+		if v := c.Query(d.name); v != "" {
+			if *d.dst, err = parseQueryDate(v, d.endOf); err != nil {
+				return q, fmt.Errorf("invalid %s %q, use YYYY-MM-DD or RFC3339", d.name, v)
+			}
+		}
+	}
+
+	q.text = strings.TrimSpace(c.Query("q"))
+
+	if v := c.Query("list_id"); v != "" {
+		id, err := strconv.ParseUint(v, 10, 64)
+		if err != nil {
+			return q, fmt.Errorf("invalid list_id %q", v)
+		}
+		q.listID = uint(id)
+	}
+	if v := c.Query("assignee"); v != "" {
+		if _, err := strconv.ParseUint(v, 10, 64); err != nil && v != "me" && v != "none" {
+			return q, fmt.Errorf("assignee must be me, none or a user id")
+		}
+		q.assignee = v
+	}
+
+	labels := []struct {
+		name string
+		dst  *[]uint
+	}{
+		{"labels", &q.labelsAny},
+		{"labels_all", &q.labelsAll},
+		{"labels_none", &q.labelsNone},
+	}
+	for _, l := range labels {
+		if *l.dst, err = parseIDList(c.QueryArray(l.name)); err != nil {
+			return q, fmt.Errorf("invalid %s: %v", l.name, err)
+		}
+	}
+
+	if s := c.Query("sort"); s != "" {
+		if _, ok := sortColumns[s]; !ok {
+			return q, fmt.Errorf("invalid sort %q, use created, updated, date, title or status", s)
+		}
+		q.sort = s
+	}
+	switch c.DefaultQuery("order", "desc") {
+	case "asc":
+		q.desc = false
+	case "desc":
+	default:
+		return q, fmt.Errorf("order must be asc or desc")
+	}
+
+	if v := c.Query("limit"); v != "" {
+		if q.limit, err = strconv.Atoi(v); err != nil || q.limit < 1 || q.limit > maxLimit {
+			return q, fmt.Errorf("limit must be between 1 and %d", maxLimit)
+		}
+	}
+	if v := c.Query("page"); v != "" {
+		if q.page, err = strconv.Atoi(v); err != nil || q.page < 1 {
+			return q, fmt.Errorf("page must be 1 or more")
+		}
+	}
+	if v := c.Query("cursor"); v != "" {
+		if c.Query("page") != "" {
+			return q, fmt.Errorf("use either page or cursor, not both")
+		}
+		if q.cursor, err = decodeCursor(v); err != nil {
+			return q, fmt.Errorf("invalid cursor")
+		}
+	}
+	return q, nil
+}
+
+// parseIDList "1,2" atau parameter yang diulang jadi daftar id
+func parseIDList(values []string) ([]uint, error) {
+	var ids []uint
+	for _, raw := range values {
+		for _, v := range strings.Split(raw, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				continue
+			}
+			id, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				return nil, fmt.Errorf("%q is not an id", v)
+			}
+			ids = append(ids, uint(id))
+		}
+	}
+	return ids, nil
+}
+
+// parseQueryDate tanggal saja untuk batas akhir dihitung sampai akhir hari itu
+func parseQueryDate(s string, endOfDay bool) (*time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return &t, nil
+	}
+	t, err := time.ParseInLocation("2006-01-02", s, time.Local)
+	if err != nil {
+		return nil, err
+	}
+	if endOfDay {
+		t = t.AddDate(0, 0, 1).Add(-time.Nanosecond)
+	}
+	return &t, nil
+}
+
+func decodeCursor(s string) (*todoCursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	var cur todoCursor
+	if err := json.Unmarshal(data, &cur); err != nil {
+		return nil, err
+	}
+	return &cur, nil
+}
+
+func encodeCursor(cur todoCursor) string {
+	data, _ := json.Marshal(cur)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// filter pasang semua filter kecuali pagination, userID buat assignee=me
+func (q todoQuery) filter(db *gorm.DB, userID uint) *gorm.DB {
+	if q.listID != 0 {
+		db = db.Where("list_id = ?", q.listID)
+	}
+	switch q.assignee {
+	case "":
+	case "me":
+		db = db.Where("assignee_id = ?", userID)
+	case "none":
+		db = db.Where("assignee_id IS NULL")
+	default:
+		db = db.Where("assignee_id = ?", q.assignee)
+	}
+	if len(q.labelsAny) > 0 {
+		db = db.Where("id IN (?)", models.LabelTodoIDs(db.Session(&gorm.Session{NewDB: true}), q.labelsAny))
+	}
+	if len(q.labelsAll) > 0 {
+		all := models.LabelTodoIDs(db.Session(&gorm.Session{NewDB: true}), q.labelsAll).
+			Group("todo_id").Having("COUNT(DISTINCT label_id) = ?", len(uniqueIDs(q.labelsAll)))
+		db = db.Where("id IN (?)", all)
+	}
+	if len(q.labelsNone) > 0 {
+		db = db.Where("id NOT IN (?)", models.LabelTodoIDs(db.Session(&gorm.Session{NewDB: true}), q.labelsNone))
+	}
+	if len(q.statuses) > 0 {
+		db = db.Where("status IN ?", q.statuses)
+	}
+	if q.createdFrom != nil {
+		db = db.Where("created_at >= ?", *q.createdFrom)
+	}
+	if q.createdTo != nil {
+		db = db.Where("created_at <= ?", *q.createdTo)
+	}
+	if q.updatedFrom != nil {
+		db = db.Where("updated_at >= ?", *q.updatedFrom)
+	}
+	if q.updatedTo != nil {
+		db = db.Where("updated_at <= ?", *q.updatedTo)
+	}
+	if q.text != "" {
+		// LOWER ... LIKE biar sama di Postgres dan SQLite (ILIKE cuma ada di Postgres)
+		like := "%" + strings.ToLower(q.text) + "%"
+		db = db.Where("LOWER(title) LIKE ? OR LOWER(\"desc\") LIKE ?", like, like)
+	}
+	return db
+}
+
+func uniqueIDs(ids []uint) []uint {
+	seen := map[uint]bool{}
+	var out []uint
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			out = append(out, id)
+		}
+	}
+	return out
+}
+
+func (q todoQuery) isTimeSort() bool {
+	return q.sort == "created" || q.sort == "updated" || q.sort == "date"
+}
+
+// paginate urutkan lalu potong sesuai cursor atau page. id dipakai sebagai
+// pemecah seri biar urutannya stabil
+func (q todoQuery) paginate(db *gorm.DB) (*gorm.DB, error) {
+	col := sortColumns[q.sort]
+	dir, cmp := "ASC", ">"
+	if q.desc {
+		dir, cmp = "DESC", "<"
+	}
+	db = db.Order(col + " " + dir).Order("id " + dir)
+
+	if q.cursor == nil {
+		return db.Offset((q.page - 1) * q.limit).Limit(q.limit + 1), nil
+	}
+
+	var value interface{} = q.cursor.Value
+	if q.isTimeSort() {
+		t, err := time.Parse(time.RFC3339Nano, q.cursor.Value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cursor")
+		}
+		value = t
+	}
+	where := fmt.Sprintf("%s %s ? OR (%s = ? AND id %s ?)", col, cmp, col, cmp)
+	return db.Where(where, value, value, q.cursor.ID).Limit(q.limit + 1), nil
+}
+
+// cursorFor bikin next_cursor dari todo terakhir di halaman ini
+func (q todoQuery) cursorFor(t models.Todo) string {
+	var value string
+	switch q.sort {
+	case "created":
+		value = t.CreatedAt.Format(time.RFC3339Nano)
+	case "updated":
+		value = t.UpdatedAt.Format(time.RFC3339Nano)
+	case "date":
+		value = t.Timestamp.Format(time.RFC3339Nano)
+	case "title":
+		value = t.Title
+	case "status":
+		value = string(t.Status)
+	}
+	return encodeCursor(todoCursor{Value: value, ID: t.ID})
+}
@@ -0,0 +1,116 @@
+package controller
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"testing"
+	"todo/models"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// seedTodos database sementara dengan todo:
+//
+//	laporan  Pending     label 1, 2
+//	rapat    InProgress  label 1
+//	belanja  Success     label 3, deskripsi "beli susu"
+//	olahraga Pending     tanpa label, di-assign ke user 1
+func seedTodos(t *testing.T) *gorm.DB {
+	t.Helper()
+	db := openTestDB(t)
+	me := uint(1)
+	todos := []models.Todo{
+		{Title: "laporan", Status: models.Pending},
+		{Title: "rapat", Status: models.InProgress},
+		{Title: "belanja", Desc: "Beli SUSU", Status: models.Success},
+		{Title: "olahraga", Status: models.Pending, AssigneeID: &me},
+	}
+	for i := range todos {
+		todos[i].UserID = me
+		if err := db.Create(&todos[i]).Error; err != nil {
+			t.Fatal(err)
+		}
+	}
+	links := []models.TodoLabel{
+		{TodoID: todos[0].ID, LabelID: 1},
+		{TodoID: todos[0].ID, LabelID: 2},
+		{TodoID: todos[1].ID, LabelID: 1},
+		{TodoID: todos[2].ID, LabelID: 3},
+	}
+	if err := db.Create(&links).Error; err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func queryFor(t *testing.T, raw string) (todoQuery, error) {
+	t.Helper()
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/todos/my?"+raw, nil)
+	return parseTodoQuery(c)
+}
+
+func TestTodoQueryFilter(t *testing.T) {
+	db := seedTodos(t)
+	cases := []struct {
+		query string
+		want  []string
+	}{
+		{"", []string{"laporan", "rapat", "belanja", "olahraga"}},
+		{"labels=1", []string{"laporan", "rapat"}},
+		{"labels=1,3", []string{"laporan", "rapat", "belanja"}},
+		{"labels=2&labels=3", []string{"laporan", "belanja"}},
+		{"labels_all=1,2", []string{"laporan"}},
+		{"labels_all=1,1", []string{"laporan", "rapat"}},
+		{"labels_all=1,3", nil},
+		{"labels_none=1", []string{"belanja", "olahraga"}},
+		{"labels_none=1,2,3", []string{"olahraga"}},
+		{"labels=1&labels_none=2", []string{"rapat"}},
+		{"labels_all=1&labels_none=2", []string{"rapat"}},
+		{"status=Pending", []string{"laporan", "olahraga"}},
+		{"status=Pending,Success&status=InProgress", []string{"laporan", "rapat", "belanja", "olahraga"}},
+		{"q=SUSU", []string{"belanja"}},
+		{"q=RAPAT", []string{"rapat"}},
+		// OR di pencarian teks tidak boleh bocor keluar filter lain
+		{"q=susu&status=Pending", nil},
+		{"assignee=me", []string{"olahraga"}},
+		{"assignee=none", []string{"laporan", "rapat", "belanja"}},
+		{"assignee=2", nil},
+	}
+	for _, c := range cases {
+		q, err := queryFor(t, c.query)
+		if err != nil {
+			t.Errorf("%s: %v", c.query, err)
+			continue
+		}
+		var todos []models.Todo
+		if err := q.filter(db.Model(&models.Todo{}), 1).Order("id").Find(&todos).Error; err != nil {
+			t.Errorf("%s: %v", c.query, err)
+			continue
+		}
+		var got []string
+		for _, todo := range todos {
+			got = append(got, todo.Title)
+		}
+		if !reflect.DeepEqual(got, c.want) {
+			t.Errorf("%s = %v, want %v", c.query, got, c.want)
+		}
+	}
+}
+
+func TestParseTodoQueryErrors(t *testing.T) {
+	for _, raw := range []string{
+		"status=Done",
+		"labels=abc",
+		"labels_none=1,x",
+		"assignee=someone",
+		"sort=priority",
+		"created_from=kemarin",
+		"limit=0",
+	} {
+		if _, err := queryFor(t, raw); err == nil {
+			t.Errorf("%s should be rejected", raw)
+		}
+	}
+}
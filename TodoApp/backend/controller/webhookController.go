@@ -0,0 +1,222 @@
+package controller
+
+import (
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+	"todo/config"
+	"todo/models"
+	"todo/worker"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// findWebhook ambil webhook :hookID milik user
+func findWebhook(c *gin.Context, hook *models.Webhook) bool {
+	id, err := strconv.Atoi(c.Param("hookID"))
+	userID := c.MustGet("userID").(uint)
+	if err != nil || config.DB.Where("user_id = ?", userID).First(hook, id).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Webhook tidak di temukan"})
+		return false
+	}
+	return true
+}
+
+type webhookInput struct {
+	URL    *string  `json:"url"`
+	Events []string `json:"events"`
+	Active *bool    `json:"aktif"`
+}
+
+// applyWebhookInput validasi url dan daftar event lalu isi ke hook
+func applyWebhookInput(c *gin.Context, hook *models.Webhook, input webhookInput) bool {
+	if input.URL != nil {
+		u, err := url.Parse(strings.TrimSpace(*input.URL))
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : "URL must be an absolute http or https URL"})
+			return false
+		}
+		// tujuan ke jaringan internal ditolak (SSRF), dicek lagi waktu kirim
+		if err := worker.CheckWebhookURL(u); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error" : err.Error()})
+			return false
+		}
+		hook.URL = u.String()
+	}
+	if input.Events != nil {
+		for _, e := range input.Events {
+			if !models.ValidWebhookEvent(e) {
+				c.JSON(http.StatusBadRequest, gin.H{"error" : "Unknown event " + e, "allowed" : models.WebhookEvents})
+				return false
+			}
+		}
+		hook.Events = strings.Join(input.Events, ",")
+	}
+	return true
+}
+
+// GetWebhooks GET /webhooks
+func GetWebhooks(c *gin.Context) {
+	var hooks []models.Webhook
+	if err := config.DB.Where("user_id = ?", c.MustGet("userID").(uint)).Order("id").Find(&hooks).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks" : hooks})
+}
+
+// CreateWebhook POST /webhooks body {"url": "...", "events": ["todo.created"]}.
+// Secret cuma ditampilkan di response ini, simpan baik-baik
+func CreateWebhook(c *gin.Context) {
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil || input.URL == nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	hook := models.Webhook{UserID: c.MustGet("userID").(uint), Secret: models.NewWebhookSecret(), Active: true}
+	if !applyWebhookInput(c, &hook, input) {
+		return
+	}
+	if err := config.DB.Create(&hook).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{"message" : "Berhasil membuat webhook", "webhook" : hook, "secret" : hook.Secret})
+}
+
+// GetWebhook GET /webhooks/:hookID
+func GetWebhook(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhook" : hook})
+}
+
+// UpdateWebhook PUT /webhooks/:hookID, ganti url/events atau nyalakan lagi
+// webhook yang dimatikan otomatis ({"aktif": true})
+func UpdateWebhook(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "Invalid Input"})
+		return
+	}
+	if !applyWebhookInput(c, &hook, input) {
+		return
+	}
+	if input.Active != nil {
+		if *input.Active && !hook.Active {
+			hook.FailureCount = 0
+			hook.DisabledAt = nil
+			hook.DisabledReason = ""
+		}
+		hook.Active = *input.Active
+	}
+	if err := config.DB.Save(&hook).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil update webhook", "webhook" : hook})
+}
+
+// DeleteWebhook DELETE /webhooks/:hookID, kiriman yang belum terkirim dibuang
+func DeleteWebhook(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	if err := config.DB.Delete(&hook).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	config.DB.Model(&models.WebhookDelivery{}).
+		Where("webhook_id = ? AND status = ?", hook.ID, models.DeliveryPending).
+		Updates(map[string]any{"status": models.DeliveryFailed, "last_error": "webhook deleted"})
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil menghapus webhook"})
+}
+
+// RotateWebhookSecret POST /webhooks/:hookID/secret, secret lama langsung
+// tidak berlaku
+func RotateWebhookSecret(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	hook.Secret = models.NewWebhookSecret()
+	if err := config.DB.Model(&hook).Update("secret", hook.Secret).Error; err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message" : "Berhasil mengganti secret", "secret" : hook.Secret})
+}
+
+// PingWebhook POST /webhooks/:hookID/ping, kirim event webhook.ping buat ngetes receiver
+func PingWebhook(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	if err := worker.EnqueueWebhook(config.DB, hook, "webhook.ping", 0, gin.H{"webhook_id" : hook.ID, "time" : time.Now()}); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	worker.WakeWebhooks()
+	c.JSON(http.StatusAccepted, gin.H{"message" : "Ping dijadwalkan, cek GET /webhooks/" + c.Param("hookID") + "/deliveries"})
+}
+
+// GetWebhookDeliveries GET /webhooks/:hookID/deliveries?status=failed&limit=50,
+// kiriman terbaru beserta log tiap percobaannya
+func GetWebhookDeliveries(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > maxLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error" : "limit must be between 1 and 200"})
+		return
+	}
+	db := config.DB.Where("webhook_id = ?", hook.ID)
+	if status := c.Query("status"); status != "" {
+		db = db.Where("status = ?", status)
+	}
+	var deliveries []models.WebhookDelivery
+	err = db.Preload("Log", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).
+		Order("id desc").Limit(limit).Find(&deliveries).Error
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries" : deliveries})
+}
+
+// RedeliverWebhook POST /webhooks/:hookID/deliveries/:deliveryID/redeliver,
+// kirim ulang kiriman yang gagal dengan jatah percobaan baru
+func RedeliverWebhook(c *gin.Context) {
+	var hook models.Webhook
+	if !findWebhook(c, &hook) {
+		return
+	}
+	var delivery models.WebhookDelivery
+	deliveryID, err := strconv.Atoi(c.Param("deliveryID"))
+	if err != nil || config.DB.Where("webhook_id = ?", hook.ID).First(&delivery, deliveryID).Error != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error" : "Kiriman tidak di temukan"})
+		return
+	}
+	if delivery.Status == models.DeliveryPending {
+		c.JSON(http.StatusConflict, gin.H{"error" : "Delivery is still pending"})
+		return
+	}
+	if err := worker.Redeliver(&delivery); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error" : err.Error()})
+		return
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message" : "Kiriman dijadwalkan ulang", "delivery" : delivery})
+}
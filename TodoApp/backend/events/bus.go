@@ -0,0 +1,169 @@
+package events
+
+import (
+	"sync"
+	"time"
+)
+
+// tipe event yang dikirim ke client
+const (
+	TodoCreated     = "todo.created"
+	TodoUpdated     = "todo.updated"
+	TodoDeleted     = "todo.deleted"
+	TodoDescription = "todo.description"
+	// TodoStatus dikirim di samping todo.updated waktu status berubah, buat
+	// pendengar yang cuma peduli perpindahan status (misal webhook)
+	TodoStatus = "todo.status_changed"
+	// TodoMoved dikirim ke anggota list lama yang kehilangan akses waktu todo
+	// pindah list. Todo-nya masih ada, jangan diperlakukan seperti todo.deleted
+	TodoMoved = "todo.moved"
+	// TodoReminder dan TodoOverdue dikirim scheduler cuma ke penerima todo
+	// (assignee atau pembuatnya) waktu pengingat jatuh tempo / tenggat lewat
+	TodoReminder = "todo.reminder"
+	TodoOverdue  = "todo.overdue"
+)
+
+const (
+	// backlogSize banyaknya event terakhir yang disimpan buat resume Last-Event-ID
+	backlogSize = 1000
+	// bufferSize antrean per subscriber, kalau penuh subscriber diputus dan
+	// client tinggal reconnect pakai Last-Event-ID
+	bufferSize = 64
+)
+
+// Event satu perubahan todo, dikirim ke semua user di UserIDs (anggota list)
+type Event struct {
+	ID      uint64      `json:"id"`
+	Type    string      `json:"type"`
+	UserIDs []uint      `json:"-"`
+	TodoID  uint        `json:"todo_id"`
+	Data    interface{} `json:"data,omitempty"`
+	Time    time.Time   `json:"time"`
+}
+
+// StatusChange data event todo.status_changed
+type StatusChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+	Todo interface{} `json:"todo"`
+}
+
+// Moved data event todo.moved
+type Moved struct {
+	FromListID uint `json:"from_list_id"`
+	ToListID   uint `json:"to_list_id"`
+}
+
+type subscriber struct {
+	userID uint
+	ch     chan Event
+}
+
+// Bus event bus dalam proses: controller publish, handler SSE subscribe
+type Bus struct {
+	mu      sync.Mutex
+	nextID  uint64
+	backlog []Event
+	subs    map[*subscriber]struct{}
+	hooks   []func(Event)
+}
+
+func NewBus() *Bus {
+	return &Bus{subs: map[*subscriber]struct{}{}}
+}
+
+// Default bus yang dipakai controller dan worker
+var Default = NewBus()
+
+func Publish(eventType string, userIDs []uint, todoID uint, data interface{}) {
+	Default.Publish(Event{Type: eventType, UserIDs: userIDs, TodoID: todoID, Data: data})
+}
+
+// OnPublish daftarkan fungsi yang dipanggil untuk setiap event di bus Default
+func OnPublish(fn func(Event)) {
+	Default.OnPublish(fn)
+}
+
+// OnPublish fn dipanggil di goroutine publisher setelah event dikirim ke
+// subscriber, jadi jangan lama-lama
+func (b *Bus) OnPublish(fn func(Event)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.hooks = append(b.hooks, fn)
+}
+
+func (e Event) visibleTo(userID uint) bool {
+	for _, id := range e.UserIDs {
+		if id == userID {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *Bus) Publish(e Event) {
+	for _, fn := range b.publish(&e) {
+		fn(e)
+	}
+}
+
+// publish kirim ke subscriber dan balikin hook yang perlu dipanggil, hook
+// dijalankan di luar lock karena bisa nulis ke database
+func (b *Bus) publish(e *Event) []func(Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.nextID++
+	e.ID = b.nextID
+	e.Time = time.Now()
+	b.backlog = append(b.backlog, *e)
+	if len(b.backlog) > backlogSize {
+		b.backlog = b.backlog[len(b.backlog)-backlogSize:]
+	}
+
+	for s := range b.subs {
+		if !e.visibleTo(s.userID) {
+			continue
+		}
+		select {
+		case s.ch <- *e:
+		default:
+			// terlalu lambat, putus biar tidak nahan publisher
+			delete(b.subs, s)
+			close(s.ch)
+		}
+	}
+	return b.hooks
+}
+
+// Subscribe daftar ke event milik userID. Event setelah lastID yang masih
+// ada di backlog ikut dikembalikan; complete false kalau lastID sudah terlalu
+// lama (sebagian event hilang) dan client sebaiknya fetch ulang semuanya
+func (b *Bus) Subscribe(userID uint, lastID uint64) (ch <-chan Event, missed []Event, complete bool, cancel func()) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	complete = true
+	if lastID > 0 {
+		if lastID > b.nextID || (len(b.backlog) > 0 && lastID+1 < b.backlog[0].ID) {
+			complete = false
+		}
+		for _, e := range b.backlog {
+			if e.ID > lastID && e.visibleTo(userID) {
+				missed = append(missed, e)
+			}
+		}
+	}
+
+	s := &subscriber{userID: userID, ch: make(chan Event, bufferSize)}
+	b.subs[s] = struct{}{}
+	cancel = func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		if _, ok := b.subs[s]; ok {
+			delete(b.subs, s)
+			close(s.ch)
+		}
+	}
+	return s.ch, missed, complete, cancel
+}
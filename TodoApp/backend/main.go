@@ -1,34 +1,165 @@
 package main
 
 import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"time"
 	"todo/config"
+	"todo/controller"
 	"todo/models"
 	"todo/routes"
+	"todo/storage"
 	"todo/utils"
+	"todo/worker"
 
 	"github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
-	"time"
 )
 
 func main() {
-	config.ConnectDatabase()
-	models.AutoMigrate()
-	models.AutoMigrateUser()
-	utils.InitAI()
+	// config: APP_ENV=dev|test|prod, file JSON opsional lewat -config atau
+	// CONFIG_FILE, env var menimpa isi file (lihat config.Config)
+	configFile := flag.String("config", os.Getenv("CONFIG_FILE"), "path to a JSON config file")
+	// admin pertama: go run . -admin budi, atau ADMIN_USERNAME=budi.
+	// ADMIN_PASSWORD dipakai kalau usernya belum ada
+	adminUser := flag.String("admin", "", "username to promote to admin on startup")
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "usage: %s [flags]\n       %s [flags] migrate up|down|status|create ...\n", os.Args[0], os.Args[0])
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+
+	// migrate create cuma bikin file, tidak perlu config atau database
+	if flag.Arg(0) == "migrate" && flag.Arg(1) == "create" {
+		if err := runMigrate(nil, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+
+	cfg, err := config.Load(*configFile)
+	if err != nil {
+		log.Fatal("config tidak valid:\n", err)
+	}
+	// -admin menimpa setelah Load, jadi aturan admin di Validate dicek ulang
+	if *adminUser != "" {
+		cfg.Admin.Username = *adminUser
+		if err := cfg.Validate(); err != nil {
+			log.Fatal("config tidak valid:\n", err)
+		}
+	}
+	if redacted, err := json.Marshal(cfg.Redacted()); err == nil {
+		log.Printf("config (%s): %s", cfg.Env, redacted)
+	}
+	if cfg.Env == config.EnvProd {
+		gin.SetMode(gin.ReleaseMode)
+	}
+
+	// aturan perpindahan status per deployment, JSON seperti
+	// {"Pending": ["InProgress"], "InProgress": ["Success", "Failed"]}
+	if err := models.LoadTransitions(string(cfg.StatusTransitions)); err != nil {
+		log.Fatal(err)
+	}
+	utils.SetupJWT(cfg.Auth.JWTSecret, time.Duration(cfg.Auth.TokenTTL))
+
+	config.ConnectDatabase(cfg.Database)
+	if err := models.SetupJoinTables(config.DB); err != nil {
+		log.Fatal(err)
+	}
+	if flag.Arg(0) == "migrate" {
+		if err := runMigrate(config.DB, flag.Args()[1:]); err != nil {
+			log.Fatal(err)
+		}
+		return
+	}
+	if err := migrateOnStart(cfg.Database.AutoMigrate); err != nil {
+		log.Fatal(err)
+	}
+	if err := models.BackfillLists(config.DB); err != nil {
+		log.Fatal("gagal memindahkan todo lama ke list ", err)
+	}
+	if cfg.Admin.Username != "" {
+		if err := models.BootstrapAdmin(cfg.Admin.Username, cfg.Admin.Password); err != nil {
+			log.Fatal("gagal bootstrap admin ", err)
+		}
+	}
+
+	aiProvider := cfg.AI.Provider
+	if aiProvider == "" {
+		aiProvider = "none"
+		if cfg.AI.APIKey != "" {
+			aiProvider = "gemini"
+		}
+	}
+	err = utils.SetupAI(utils.AIConfig{
+		Provider: aiProvider,
+		Model:    cfg.AI.Model,
+		BaseURL:  cfg.AI.BaseURL,
+		APIKey:   cfg.AI.APIKey,
+		Timeout:  time.Duration(cfg.AI.Timeout),
+	})
+	if err != nil {
+		log.Println("AI dimatikan:", err)
+	}
+	log.Println("AI provider:", utils.AIProviderName())
+	worker.StartDescriptionWorkers(4)
+
+	err = storage.Init(storage.Config{
+		Driver:    cfg.Storage.Driver,
+		Dir:       cfg.Storage.Dir,
+		Endpoint:  cfg.Storage.Endpoint,
+		Bucket:    cfg.Storage.Bucket,
+		Region:    cfg.Storage.Region,
+		AccessKey: cfg.Storage.AccessKey,
+		SecretKey: cfg.Storage.SecretKey,
+	})
+	if err != nil {
+		log.Fatal("gagal menyiapkan storage ", err)
+	}
+	controller.SetUploadLimits(int64(cfg.Storage.MaxUploadMB)<<20, cfg.Storage.AllowedTypes)
+
+	worker.StartScheduler(worker.SchedulerConfig{
+		Interval:    time.Duration(cfg.Scheduler.Interval),
+		FailOverdue: cfg.Scheduler.FailOverdue,
+		Notifier:    worker.EventNotifier{},
+	})
+	allowPrivate, err := worker.ParseAllowList(cfg.Webhooks.AllowPrivate)
+	if err != nil {
+		log.Fatal("WEBHOOK_ALLOW_PRIVATE: ", err)
+	}
+	worker.StartWebhookWorker(worker.WebhookConfig{
+		MaxAttempts:  cfg.Webhooks.MaxAttempts,
+		DisableAfter: cfg.Webhooks.DisableAfter,
+		AllowPrivate: allowPrivate,
+	})
 
 	r := gin.Default()
 
-	// Allow semua origin
-	r.Use(cors.New(cors.Config{
-		AllowAllOrigins: true, // <--- ini buat allow semua
-		AllowMethods:    []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
-		AllowHeaders:    []string{"Origin", "Content-Type", "Authorization"},
-		ExposeHeaders:   []string{"Content-Length"},
-		MaxAge:          12 * time.Hour,
-	}))
+	// origin dari CORS_ORIGINS, "*" (default dev) artinya semua origin
+	corsConfig := cors.Config{
+		AllowMethods:  []string{"GET", "POST", "PUT", "DELETE", "OPTIONS"},
+		AllowHeaders:  []string{"Origin", "Content-Type", "Authorization"},
+		ExposeHeaders: []string{"Content-Length"},
+		MaxAge:        12 * time.Hour,
+	}
+	for _, origin := range cfg.Server.CORSOrigins {
+		if origin == "*" {
+			corsConfig.AllowAllOrigins = true
+		}
+	}
+	if !corsConfig.AllowAllOrigins {
+		corsConfig.AllowOrigins = cfg.Server.CORSOrigins
+	}
+	if len(cfg.Server.CORSOrigins) > 0 {
+		r.Use(cors.New(corsConfig))
+	}
 
 	routes.TodoRoutes(r)
 
-	r.Run(":7002")
+	if err := r.Run(cfg.Server.Addr); err != nil {
+		log.Fatal(err)
+	}
 }
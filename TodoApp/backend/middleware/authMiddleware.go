@@ -3,6 +3,8 @@ package middleware
 import (
 	"net/http"
 	"strings"
+	"todo/config"
+	"todo/models"
 	"todo/utils"
 
 	"github.com/gin-gonic/gin"
@@ -31,8 +33,18 @@ func AuthMiddleware() gin.HandlerFunc {
 			return
 		}
 
+		// token lama tetap ditolak kalau akunnya sudah di-disable admin,
+		// role juga diambil dari DB biar perubahan role langsung berlaku
+		var user models.User
+		if err := config.DB.Select("id", "role", "disabled").First(&user, claims.UserID).Error; err != nil || user.Disabled {
+			c.JSON(http.StatusUnauthorized, gin.H{"error" : "Account not found or disabled"})
+			c.Abort()
+			return
+		}
+
 		c.Set("userID", claims.UserID)
 		c.Set("username", claims.Username)
+		c.Set("role", user.Role)
 		c.Next()
 
 	}
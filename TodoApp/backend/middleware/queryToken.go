@@ -0,0 +1,15 @@
+package middleware
+
+import "github.com/gin-gonic/gin"
+
+// TokenFromQuery buat EventSource di browser yang tidak bisa kirim header:
+// ?token=<jwt> dipindah ke Authorization sebelum AuthMiddleware jalan.
+// Pasang cuma di route stream, jangan di semua route
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if token := c.Query("token"); token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
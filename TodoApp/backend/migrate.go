@@ -0,0 +1,113 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"todo/config"
+	"todo/migrations"
+
+	"gorm.io/gorm"
+)
+
+const migrateUsage = `usage: migrate up [n]      jalankan n migration berikutnya (default semua)
+       migrate down [n]    batalkan n migration terakhir (default 1)
+       migrate status      daftar migration dan kapan dijalankan
+       migrate create <nama>  bikin file up/down baru di ` + migrations.Dir
+
+// runMigrate subcommand migrate, args tanpa kata "migrate"
+func runMigrate(db *gorm.DB, args []string) error {
+	if len(args) == 0 {
+		return errors.New(migrateUsage)
+	}
+	steps := func(def int) (int, error) {
+		if len(args) < 2 {
+			return def, nil
+		}
+		n, err := strconv.Atoi(args[1])
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("invalid number of steps %q", args[1])
+		}
+		return n, nil
+	}
+
+	switch args[0] {
+	case "create":
+		if len(args) < 2 {
+			return errors.New("usage: migrate create <name>")
+		}
+		created, err := migrations.Create(migrations.Dir, strings.Join(args[1:], "_"))
+		for _, path := range created {
+			fmt.Println("created", path)
+		}
+		return err
+	case "up":
+		n, err := steps(0)
+		if err != nil {
+			return err
+		}
+		done, err := migrations.Up(db, n)
+		for _, m := range done {
+			fmt.Printf("up   %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Println("nothing to migrate")
+		}
+		return err
+	case "down":
+		n, err := steps(1)
+		if err != nil {
+			return err
+		}
+		done, err := migrations.Down(db, n)
+		for _, m := range done {
+			fmt.Printf("down %04d_%s\n", m.Version, m.Name)
+		}
+		if err == nil && len(done) == 0 {
+			fmt.Println("nothing to roll back")
+		}
+		return err
+	case "status":
+		states, err := migrations.Status(db)
+		if err != nil {
+			return err
+		}
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "VERSION\tNAME\tAPPLIED")
+		for _, s := range states {
+			applied := "pending"
+			if s.AppliedAt != nil {
+				applied = s.AppliedAt.Format("2006-01-02 15:04:05")
+			}
+			fmt.Fprintf(w, "%04d\t%s\t%s\n", s.Version, s.Name, applied)
+		}
+		return w.Flush()
+	default:
+		return errors.New(migrateUsage)
+	}
+}
+
+// migrateOnStart jalankan migration pending kalau auto migrate nyala,
+// kalau tidak server menolak start daripada jalan di skema lama
+func migrateOnStart(auto bool) error {
+	if auto {
+		done, err := migrations.Up(config.DB, 0)
+		for _, m := range done {
+			log.Printf("migration %04d_%s dijalankan", m.Version, m.Name)
+		}
+		return err
+	}
+	pending, err := migrations.Pending(config.DB)
+	if err != nil {
+		return err
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%d migration belum dijalankan (terbaru %04d_%s), jalankan: %s migrate up",
+			len(pending), pending[len(pending)-1].Version, pending[len(pending)-1].Name, os.Args[0])
+	}
+	return nil
+}
@@ -0,0 +1,282 @@
+// Package migrations skema database yang berversi. Tiap migration sepasang
+// file di sql/: NNNN_nama.up.sql dan NNNN_nama.down.sql. Kalau SQL-nya beda
+// antara Postgres dan SQLite, tambahkan nama driver: NNNN_nama.up.postgres.sql
+// dan NNNN_nama.up.sqlite.sql. Migration tanpa file down (misal baseline)
+// tidak bisa dibatalkan. Versi yang sudah jalan dicatat di tabel
+// schema_migrations
+package migrations
+
+import (
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+//go:embed sql/*.sql
+var files embed.FS
+
+// Dir folder file migration relatif terhadap root backend, dipakai Create
+const Dir = "migrations/sql"
+
+// Migration satu versi skema dengan SQL up/down untuk driver yang dipakai
+type Migration struct {
+	Version int64
+	Name    string
+	Up      string
+	Down    string
+}
+
+// State status migration di database, AppliedAt nil kalau belum dijalankan
+type State struct {
+	Migration
+	AppliedAt *time.Time
+}
+
+// schemaMigration baris tabel schema_migrations
+type schemaMigration struct {
+	Version   int64 `gorm:"primaryKey;autoIncrement:false"`
+	Name      string
+	AppliedAt time.Time
+}
+
+func (schemaMigration) TableName() string {
+	return "schema_migrations"
+}
+
+var fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+)\.(up|down)(?:\.(postgres|sqlite))?\.sql$`)
+
+// lockID kunci advisory Postgres biar dua instance tidak migrate bareng
+const lockID = 7002_0050
+
+// Load baca semua migration untuk driver ("postgres" atau "sqlite"),
+// urut dari versi paling lama. File khusus driver menang atas file umum
+func Load(driver string) ([]Migration, error) {
+	entries, err := fs.ReadDir(files, "sql")
+	if err != nil {
+		return nil, err
+	}
+	byVersion := map[int64]*Migration{}
+	// specific[versi/arah] true kalau sudah diisi file khusus driver
+	specific := map[string]bool{}
+	for _, e := range entries {
+		m := fileName.FindStringSubmatch(e.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q", e.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		name, direction, only := m[2], m[3], m[4]
+		if only != "" && only != driver {
+			continue
+		}
+		mig, ok := byVersion[version]
+		if !ok {
+			mig = &Migration{Version: version, Name: name}
+			byVersion[version] = mig
+		}
+		if mig.Name != name {
+			return nil, fmt.Errorf("migration %d has two names: %s and %s", version, mig.Name, name)
+		}
+		key := m[1] + direction
+		if only == "" && specific[key] {
+			continue
+		}
+		data, err := files.ReadFile("sql/" + e.Name())
+		if err != nil {
+			return nil, err
+		}
+		if direction == "up" {
+			mig.Up = string(data)
+		} else {
+			mig.Down = string(data)
+		}
+		if only != "" {
+			specific[key] = true
+		}
+	}
+
+	list := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if strings.TrimSpace(m.Up) == "" {
+			return nil, fmt.Errorf("migration %04d_%s has no up SQL for %s", m.Version, m.Name, driver)
+		}
+		list = append(list, *m)
+	}
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+func ensureTable(db *gorm.DB) error {
+	return db.AutoMigrate(&schemaMigration{})
+}
+
+func applied(db *gorm.DB) (map[int64]time.Time, error) {
+	var rows []schemaMigration
+	if err := db.Find(&rows).Error; err != nil {
+		return nil, err
+	}
+	done := make(map[int64]time.Time, len(rows))
+	for _, r := range rows {
+		done[r.Version] = r.AppliedAt
+	}
+	return done, nil
+}
+
+// Status semua migration beserta kapan dijalankan
+func Status(db *gorm.DB) ([]State, error) {
+	list, err := Load(db.Dialector.Name())
+	if err != nil {
+		return nil, err
+	}
+	if err := ensureTable(db); err != nil {
+		return nil, err
+	}
+	done, err := applied(db)
+	if err != nil {
+		return nil, err
+	}
+	states := make([]State, 0, len(list))
+	for _, m := range list {
+		s := State{Migration: m}
+		if at, ok := done[m.Version]; ok {
+			s.AppliedAt = &at
+		}
+		states = append(states, s)
+	}
+	return states, nil
+}
+
+// Pending migration yang belum dijalankan
+func Pending(db *gorm.DB) ([]Migration, error) {
+	states, err := Status(db)
+	if err != nil {
+		return nil, err
+	}
+	var pending []Migration
+	for _, s := range states {
+		if s.AppliedAt == nil {
+			pending = append(pending, s.Migration)
+		}
+	}
+	return pending, nil
+}
+
+// Up jalankan migration yang belum jalan, paling banyak steps (0 = semua).
+// Tiap migration jalan di transaksinya sendiri bareng pencatatan versinya
+func Up(db *gorm.DB, steps int) ([]Migration, error) {
+	pending, err := Pending(db)
+	if err != nil {
+		return nil, err
+	}
+	if steps > 0 && len(pending) > steps {
+		pending = pending[:steps]
+	}
+	var done []Migration
+	for _, m := range pending {
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if skip, err := lock(tx, m.Version, true); err != nil || skip {
+				return err
+			}
+			if err := tx.Exec(m.Up).Error; err != nil {
+				return err
+			}
+			return tx.Create(&schemaMigration{Version: m.Version, Name: m.Name, AppliedAt: time.Now()}).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s up: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m)
+	}
+	return done, nil
+}
+
+// Down batalkan steps migration terakhir yang sudah jalan
+func Down(db *gorm.DB, steps int) ([]Migration, error) {
+	if steps <= 0 {
+		return nil, errors.New("steps must be at least 1")
+	}
+	states, err := Status(db)
+	if err != nil {
+		return nil, err
+	}
+	var done []Migration
+	for i := len(states) - 1; i >= 0 && len(done) < steps; i-- {
+		m := states[i]
+		if m.AppliedAt == nil {
+			continue
+		}
+		if strings.TrimSpace(m.Down) == "" {
+			return done, fmt.Errorf("migration %04d_%s has no down SQL and cannot be rolled back", m.Version, m.Name)
+		}
+		err := db.Transaction(func(tx *gorm.DB) error {
+			if skip, err := lock(tx, m.Version, false); err != nil || skip {
+				return err
+			}
+			if err := tx.Exec(m.Down).Error; err != nil {
+				return err
+			}
+			return tx.Delete(&schemaMigration{}, m.Version).Error
+		})
+		if err != nil {
+			return done, fmt.Errorf("migration %04d_%s down: %w", m.Version, m.Name, err)
+		}
+		done = append(done, m.Migration)
+	}
+	return done, nil
+}
+
+// lock di Postgres ambil advisory lock sampai transaksi selesai, lalu cek
+// ulang versinya: skip true kalau instance lain sudah duluan ngerjain
+func lock(tx *gorm.DB, version int64, up bool) (skip bool, err error) {
+	if tx.Dialector.Name() == "postgres" {
+		if err := tx.Exec("SELECT pg_advisory_xact_lock(?)", lockID).Error; err != nil {
+			return false, err
+		}
+	}
+	var count int64
+	if err := tx.Model(&schemaMigration{}).Where("version = ?", version).Count(&count).Error; err != nil {
+		return false, err
+	}
+	return (count > 0) == up, nil
+}
+
+// Create bikin pasangan file up/down kosong dengan versi berikutnya di dir
+func Create(dir, name string) ([]string, error) {
+	name = strings.Trim(regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(strings.ToLower(name), "_"), "_")
+	if name == "" {
+		return nil, errors.New("migration name is required")
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var last int64
+	for _, e := range entries {
+		if m := fileName.FindStringSubmatch(e.Name()); m != nil {
+			if v, _ := strconv.ParseInt(m[1], 10, 64); v > last {
+				last = v
+			}
+		}
+	}
+
+	base := fmt.Sprintf("%04d_%s", last+1, name)
+	var created []string
+	for _, direction := range []string{"up", "down"} {
+		path := filepath.Join(dir, base+"."+direction+".sql")
+		content := fmt.Sprintf("-- %s: %s\n", base, direction)
+		if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+			return created, err
+		}
+		created = append(created, path)
+	}
+	return created, nil
+}
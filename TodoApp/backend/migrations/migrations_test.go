@@ -0,0 +1,131 @@
+package migrations
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func appliedCount(t *testing.T, db *gorm.DB) int {
+	t.Helper()
+	states, err := Status(db)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n := 0
+	for _, s := range states {
+		if s.AppliedAt != nil {
+			n++
+		}
+	}
+	return n
+}
+
+func TestLoad(t *testing.T) {
+	for _, driver := range []string{"sqlite", "postgres"} {
+		list, err := Load(driver)
+		if err != nil {
+			t.Fatalf("%s: %v", driver, err)
+		}
+		if len(list) == 0 || list[0].Version != 1 || list[0].Down != "" {
+			t.Fatalf("%s: first migration should be the irreversible baseline, got %+v", driver, list[0])
+		}
+		for i, m := range list {
+			if i > 0 && m.Version <= list[i-1].Version {
+				t.Errorf("%s: versions out of order at %d", driver, m.Version)
+			}
+			if i > 0 && strings.TrimSpace(m.Down) == "" {
+				t.Errorf("%s: %04d_%s has no down SQL", driver, m.Version, m.Name)
+			}
+		}
+	}
+}
+
+func TestUpStatusDown(t *testing.T) {
+	db := openDB(t)
+	total, _ := Load("sqlite")
+
+	done, err := Up(db, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(done) != 2 || appliedCount(t, db) != 2 {
+		t.Fatalf("Up(2) applied %d, status says %d", len(done), appliedCount(t, db))
+	}
+	if !db.Migrator().HasColumn("users", "role") {
+		t.Fatal("0002 should add users.role")
+	}
+
+	if _, err := Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	if n := appliedCount(t, db); n != len(total) {
+		t.Fatalf("applied %d of %d", n, len(total))
+	}
+	pending, err := Pending(db)
+	if err != nil || len(pending) != 0 {
+		t.Fatalf("pending after Up: %v, %v", pending, err)
+	}
+	for _, table := range []string{"lists", "labels", "todo_labels", "webhooks", "webhook_deliveries"} {
+		if !db.Migrator().HasTable(table) {
+			t.Errorf("table %s missing after Up", table)
+		}
+	}
+
+	// ulang Up tidak ngapa-ngapain
+	if again, err := Up(db, 0); err != nil || len(again) != 0 {
+		t.Fatalf("second Up: %v, %v", again, err)
+	}
+
+	rolled, err := Down(db, 1)
+	if err != nil || len(rolled) != 1 || rolled[0].Name != "webhooks" {
+		t.Fatalf("Down(1) = %v, %v", rolled, err)
+	}
+	if db.Migrator().HasTable("webhooks") {
+		t.Fatal("webhooks table should be dropped")
+	}
+
+	// turun terus sampai baseline, yang tidak bisa dibatalkan
+	rolled, err = Down(db, len(total))
+	if err == nil || !strings.Contains(err.Error(), "0001_baseline") {
+		t.Fatalf("Down past baseline: err = %v", err)
+	}
+	if len(rolled) != len(total)-2 || appliedCount(t, db) != 1 {
+		t.Fatalf("rolled back %d, %d still applied", len(rolled), appliedCount(t, db))
+	}
+	if db.Migrator().HasColumn("users", "role") || !db.Migrator().HasTable("todos") {
+		t.Fatal("only the baseline schema should be left")
+	}
+
+	if _, err := Up(db, 0); err != nil {
+		t.Fatalf("Up after Down: %v", err)
+	}
+	if n := appliedCount(t, db); n != len(total) {
+		t.Fatalf("applied %d of %d after re-Up", n, len(total))
+	}
+}
+
+func TestDownSteps(t *testing.T) {
+	db := openDB(t)
+	if _, err := Down(db, 0); err == nil {
+		t.Fatal("Down(0) should fail")
+	}
+	if rolled, err := Down(db, 1); err != nil || len(rolled) != 0 {
+		t.Fatalf("Down on empty database = %v, %v", rolled, err)
+	}
+}
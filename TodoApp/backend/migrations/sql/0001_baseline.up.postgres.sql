@@ -0,0 +1,9 @@
+-- skema awal sebelum ada migration: cuma users dan todos, sama dengan hasil
+-- GORM AutoMigrate versi pertama. IF NOT EXISTS biar database lama langsung
+-- naik. Tidak ada file down, baseline tidak bisa dibatalkan
+
+CREATE TABLE IF NOT EXISTS "users" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"username" text,"password" text,PRIMARY KEY ("id"),CONSTRAINT "uni_users_username" UNIQUE ("username"));
+CREATE INDEX IF NOT EXISTS "idx_users_deleted_at" ON "users" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "todos" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"title" text,"desc" text,"timestamp" timestamptz,"status" text DEFAULT 'Pending',"user_id" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_users_todos" FOREIGN KEY ("user_id") REFERENCES "users"("id"));
+CREATE INDEX IF NOT EXISTS "idx_todos_deleted_at" ON "todos" ("deleted_at");
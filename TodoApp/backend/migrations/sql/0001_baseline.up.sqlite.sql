@@ -0,0 +1,8 @@
+-- skema awal sebelum ada migration: cuma users dan todos. Tidak ada file
+-- down, baseline tidak bisa dibatalkan
+
+CREATE TABLE IF NOT EXISTS `users` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`username` text,`password` text,CONSTRAINT `uni_users_username` UNIQUE (`username`));
+CREATE INDEX IF NOT EXISTS `idx_users_deleted_at` ON `users`(`deleted_at`);
+
+CREATE TABLE IF NOT EXISTS `todos` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`title` text,`desc` text,`timestamp` datetime,`status` text DEFAULT "Pending",`user_id` integer,CONSTRAINT `fk_users_todos` FOREIGN KEY (`user_id`) REFERENCES `users`(`id`));
+CREATE INDEX IF NOT EXISTS `idx_todos_deleted_at` ON `todos`(`deleted_at`);
@@ -0,0 +1,4 @@
+-- IF NOT EXISTS buat database Postgres yang dibuat AutoMigrate versi antara
+
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "role" text DEFAULT 'user';
+ALTER TABLE "users" ADD COLUMN IF NOT EXISTS "disabled" boolean;
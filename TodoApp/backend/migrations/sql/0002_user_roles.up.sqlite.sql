@@ -0,0 +1,2 @@
+ALTER TABLE `users` ADD COLUMN `role` text DEFAULT "user";
+ALTER TABLE `users` ADD COLUMN `disabled` numeric;
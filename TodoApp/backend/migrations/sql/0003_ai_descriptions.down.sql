@@ -0,0 +1,2 @@
+ALTER TABLE todos DROP COLUMN desc_status;
+ALTER TABLE todos DROP COLUMN desc_error;
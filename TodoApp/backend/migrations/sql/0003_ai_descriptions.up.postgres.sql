@@ -0,0 +1,4 @@
+-- IF NOT EXISTS buat database Postgres yang dibuat AutoMigrate versi antara
+
+ALTER TABLE "todos" ADD COLUMN IF NOT EXISTS "desc_status" text DEFAULT 'manual';
+ALTER TABLE "todos" ADD COLUMN IF NOT EXISTS "desc_error" text;
@@ -0,0 +1,2 @@
+ALTER TABLE `todos` ADD COLUMN `desc_status` text DEFAULT "manual";
+ALTER TABLE `todos` ADD COLUMN `desc_error` text;
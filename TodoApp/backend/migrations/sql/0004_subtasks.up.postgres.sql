@@ -0,0 +1,3 @@
+CREATE TABLE IF NOT EXISTS "subtasks" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"todo_id" bigint,"title" text,"status" text DEFAULT 'Pending',"position" bigint,PRIMARY KEY ("id"),CONSTRAINT "fk_todos_subtasks" FOREIGN KEY ("todo_id") REFERENCES "todos"("id"));
+CREATE INDEX IF NOT EXISTS "idx_subtasks_todo_id" ON "subtasks" ("todo_id");
+CREATE INDEX IF NOT EXISTS "idx_subtasks_deleted_at" ON "subtasks" ("deleted_at");
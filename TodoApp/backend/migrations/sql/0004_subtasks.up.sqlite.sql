@@ -0,0 +1,3 @@
+CREATE TABLE `subtasks` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`todo_id` integer,`title` text,`status` text DEFAULT "Pending",`position` integer,CONSTRAINT `fk_todos_subtasks` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`));
+CREATE INDEX `idx_subtasks_todo_id` ON `subtasks`(`todo_id`);
+CREATE INDEX `idx_subtasks_deleted_at` ON `subtasks`(`deleted_at`);
@@ -0,0 +1,4 @@
+-- SQLite tidak bisa drop kolom yang masih punya index
+DROP INDEX idx_todos_due_at;
+ALTER TABLE todos DROP COLUMN due_at;
+ALTER TABLE todos DROP COLUMN priority;
@@ -0,0 +1,5 @@
+-- IF NOT EXISTS buat database Postgres yang dibuat AutoMigrate versi antara
+
+ALTER TABLE "todos" ADD COLUMN IF NOT EXISTS "priority" text DEFAULT 'normal';
+ALTER TABLE "todos" ADD COLUMN IF NOT EXISTS "due_at" timestamptz;
+CREATE INDEX IF NOT EXISTS "idx_todos_due_at" ON "todos" ("due_at");
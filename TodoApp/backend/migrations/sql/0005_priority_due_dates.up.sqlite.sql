@@ -0,0 +1,3 @@
+ALTER TABLE `todos` ADD COLUMN `priority` text DEFAULT "normal";
+ALTER TABLE `todos` ADD COLUMN `due_at` datetime;
+CREATE INDEX `idx_todos_due_at` ON `todos`(`due_at`);
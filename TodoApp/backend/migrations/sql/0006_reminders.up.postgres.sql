@@ -0,0 +1,4 @@
+CREATE TABLE IF NOT EXISTS "reminders" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"todo_id" bigint,"offset_minutes" bigint,"remind_at" timestamptz,"sent_at" timestamptz,PRIMARY KEY ("id"),CONSTRAINT "fk_todos_reminders" FOREIGN KEY ("todo_id") REFERENCES "todos"("id"));
+CREATE INDEX IF NOT EXISTS "idx_reminders_remind_at" ON "reminders" ("remind_at");
+CREATE INDEX IF NOT EXISTS "idx_reminders_todo_id" ON "reminders" ("todo_id");
+CREATE INDEX IF NOT EXISTS "idx_reminders_deleted_at" ON "reminders" ("deleted_at");
@@ -0,0 +1,4 @@
+CREATE TABLE `reminders` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`todo_id` integer,`offset_minutes` integer,`remind_at` datetime,`sent_at` datetime,CONSTRAINT `fk_todos_reminders` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`));
+CREATE INDEX `idx_reminders_remind_at` ON `reminders`(`remind_at`);
+CREATE INDEX `idx_reminders_todo_id` ON `reminders`(`todo_id`);
+CREATE INDEX `idx_reminders_deleted_at` ON `reminders`(`deleted_at`);
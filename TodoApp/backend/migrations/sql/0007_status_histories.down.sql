@@ -0,0 +1 @@
+DROP TABLE status_histories;
@@ -0,0 +1,2 @@
+CREATE TABLE IF NOT EXISTS "status_histories" ("id" bigserial,"todo_id" bigint,"from" text,"to" text,"actor_id" bigint,"reason" text,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_status_histories_todo_id" ON "status_histories" ("todo_id");
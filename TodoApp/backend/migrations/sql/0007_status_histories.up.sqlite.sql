@@ -0,0 +1,2 @@
+CREATE TABLE `status_histories` (`id` integer PRIMARY KEY AUTOINCREMENT,`todo_id` integer,`from` text,`to` text,`actor_id` integer,`reason` text,`created_at` datetime);
+CREATE INDEX `idx_status_histories_todo_id` ON `status_histories`(`todo_id`);
@@ -0,0 +1,7 @@
+-- SQLite tidak bisa drop kolom yang masih punya index
+DROP INDEX idx_todos_list_id;
+ALTER TABLE todos DROP COLUMN assignee_id;
+ALTER TABLE todos DROP COLUMN list_id;
+DROP TABLE list_invites;
+DROP TABLE list_members;
+DROP TABLE lists;
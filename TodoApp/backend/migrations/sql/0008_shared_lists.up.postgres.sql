@@ -0,0 +1,18 @@
+-- IF NOT EXISTS buat database Postgres yang dibuat AutoMigrate versi antara
+
+CREATE TABLE IF NOT EXISTS "lists" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"name" text,"owner_id" bigint,"personal" boolean,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_lists_deleted_at" ON "lists" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "list_members" ("id" bigserial,"list_id" bigint,"user_id" bigint,"role" text,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_list_members_user_id" ON "list_members" ("user_id");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_list_member" ON "list_members" ("list_id","user_id");
+
+CREATE TABLE IF NOT EXISTS "list_invites" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"list_id" bigint,"inviter_id" bigint,"invitee_id" bigint,"role" text,"status" text DEFAULT 'pending',PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_list_invites_invitee_id" ON "list_invites" ("invitee_id");
+CREATE INDEX IF NOT EXISTS "idx_list_invites_list_id" ON "list_invites" ("list_id");
+CREATE INDEX IF NOT EXISTS "idx_list_invites_deleted_at" ON "list_invites" ("deleted_at");
+
+-- todo lama dapat list Personal lewat models.BackfillLists waktu start
+ALTER TABLE "todos" ADD COLUMN IF NOT EXISTS "list_id" bigint;
+ALTER TABLE "todos" ADD COLUMN IF NOT EXISTS "assignee_id" bigint;
+CREATE INDEX IF NOT EXISTS "idx_todos_list_id" ON "todos" ("list_id");
@@ -0,0 +1,16 @@
+CREATE TABLE `lists` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`name` text,`owner_id` integer,`personal` numeric);
+CREATE INDEX `idx_lists_deleted_at` ON `lists`(`deleted_at`);
+
+CREATE TABLE `list_members` (`id` integer PRIMARY KEY AUTOINCREMENT,`list_id` integer,`user_id` integer,`role` text,`created_at` datetime);
+CREATE INDEX `idx_list_members_user_id` ON `list_members`(`user_id`);
+CREATE UNIQUE INDEX `idx_list_member` ON `list_members`(`list_id`,`user_id`);
+
+CREATE TABLE `list_invites` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`list_id` integer,`inviter_id` integer,`invitee_id` integer,`role` text,`status` text DEFAULT "pending");
+CREATE INDEX `idx_list_invites_invitee_id` ON `list_invites`(`invitee_id`);
+CREATE INDEX `idx_list_invites_list_id` ON `list_invites`(`list_id`);
+CREATE INDEX `idx_list_invites_deleted_at` ON `list_invites`(`deleted_at`);
+
+-- todo lama dapat list Personal lewat models.BackfillLists waktu start
+ALTER TABLE `todos` ADD COLUMN `list_id` integer;
+ALTER TABLE `todos` ADD COLUMN `assignee_id` integer;
+CREATE INDEX `idx_todos_list_id` ON `todos`(`list_id`);
@@ -0,0 +1,3 @@
+-- file lampiran di storage tidak ikut terhapus
+DROP TABLE attachments;
+DROP TABLE comments;
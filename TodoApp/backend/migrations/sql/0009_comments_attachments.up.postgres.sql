@@ -0,0 +1,8 @@
+CREATE TABLE IF NOT EXISTS "comments" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"todo_id" bigint,"author_id" bigint,"parent_id" bigint,"body" text,"edited" boolean,"removed" boolean,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_comments_parent_id" ON "comments" ("parent_id");
+CREATE INDEX IF NOT EXISTS "idx_comments_todo_id" ON "comments" ("todo_id");
+CREATE INDEX IF NOT EXISTS "idx_comments_deleted_at" ON "comments" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "attachments" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"todo_id" bigint,"uploader_id" bigint,"file_name" text,"content_type" text,"size" bigint,"storage_key" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_attachments_todo_id" ON "attachments" ("todo_id");
+CREATE INDEX IF NOT EXISTS "idx_attachments_deleted_at" ON "attachments" ("deleted_at");
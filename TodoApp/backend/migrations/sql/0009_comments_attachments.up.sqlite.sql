@@ -0,0 +1,8 @@
+CREATE TABLE `comments` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`todo_id` integer,`author_id` integer,`parent_id` integer,`body` text,`edited` numeric,`removed` numeric);
+CREATE INDEX `idx_comments_parent_id` ON `comments`(`parent_id`);
+CREATE INDEX `idx_comments_todo_id` ON `comments`(`todo_id`);
+CREATE INDEX `idx_comments_deleted_at` ON `comments`(`deleted_at`);
+
+CREATE TABLE `attachments` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`todo_id` integer,`uploader_id` integer,`file_name` text,`content_type` text,`size` integer,`storage_key` text);
+CREATE INDEX `idx_attachments_todo_id` ON `attachments`(`todo_id`);
+CREATE INDEX `idx_attachments_deleted_at` ON `attachments`(`deleted_at`);
@@ -0,0 +1,2 @@
+DROP TABLE todo_labels;
+DROP TABLE labels;
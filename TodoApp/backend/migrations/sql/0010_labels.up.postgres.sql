@@ -0,0 +1,5 @@
+CREATE TABLE IF NOT EXISTS "labels" ("id" bigserial,"owner_id" bigint,"name" text,"color" text,"created_at" timestamptz,PRIMARY KEY ("id"));
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_label_owner_name" ON "labels" ("owner_id","name");
+
+CREATE TABLE IF NOT EXISTS "todo_labels" ("todo_id" bigint,"label_id" bigint,PRIMARY KEY ("todo_id","label_id"),CONSTRAINT "fk_todo_labels_todo" FOREIGN KEY ("todo_id") REFERENCES "todos"("id"),CONSTRAINT "fk_todo_labels_label" FOREIGN KEY ("label_id") REFERENCES "labels"("id"));
+CREATE INDEX IF NOT EXISTS "idx_todo_labels_label_id" ON "todo_labels" ("label_id");
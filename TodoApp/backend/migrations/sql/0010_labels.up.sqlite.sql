@@ -0,0 +1,5 @@
+CREATE TABLE `labels` (`id` integer PRIMARY KEY AUTOINCREMENT,`owner_id` integer,`name` text,`color` text,`created_at` datetime);
+CREATE UNIQUE INDEX `idx_label_owner_name` ON `labels`(`owner_id`,`name`);
+
+CREATE TABLE `todo_labels` (`todo_id` integer,`label_id` integer,PRIMARY KEY (`todo_id`,`label_id`),CONSTRAINT `fk_todo_labels_label` FOREIGN KEY (`label_id`) REFERENCES `labels`(`id`),CONSTRAINT `fk_todo_labels_todo` FOREIGN KEY (`todo_id`) REFERENCES `todos`(`id`));
+CREATE INDEX `idx_todo_labels_label_id` ON `todo_labels`(`label_id`);
@@ -0,0 +1,12 @@
+CREATE TABLE IF NOT EXISTS "webhooks" ("id" bigserial,"created_at" timestamptz,"updated_at" timestamptz,"deleted_at" timestamptz,"user_id" bigint,"url" text,"secret" text,"events" text,"active" boolean DEFAULT true,"failure_count" bigint,"disabled_at" timestamptz,"disabled_reason" text,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhooks_user_id" ON "webhooks" ("user_id");
+CREATE INDEX IF NOT EXISTS "idx_webhooks_deleted_at" ON "webhooks" ("deleted_at");
+
+CREATE TABLE IF NOT EXISTS "webhook_deliveries" ("id" bigserial,"created_at" timestamptz,"webhook_id" bigint,"event_id" text,"event_type" text,"payload" text,"status" text,"attempts" bigint,"next_attempt_at" timestamptz,"last_error" text,"delivered_at" timestamptz,PRIMARY KEY ("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_status" ON "webhook_deliveries" ("status");
+CREATE UNIQUE INDEX IF NOT EXISTS "idx_webhook_deliveries_event_id" ON "webhook_deliveries" ("event_id");
+CREATE INDEX IF NOT EXISTS "idx_webhook_deliveries_webhook_id" ON "webhook_deliveries" ("webhook_id");
+
+CREATE TABLE IF NOT EXISTS "webhook_attempts" ("id" bigserial,"created_at" timestamptz,"delivery_id" bigint,"status_code" bigint,"error" text,"duration_ms" bigint,"response" text,PRIMARY KEY ("id"),CONSTRAINT "fk_webhook_deliveries_log" FOREIGN KEY ("delivery_id") REFERENCES "webhook_deliveries"("id"));
+CREATE INDEX IF NOT EXISTS "idx_webhook_attempts_delivery_id" ON "webhook_attempts" ("delivery_id");
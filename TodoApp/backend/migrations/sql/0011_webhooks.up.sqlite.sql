@@ -0,0 +1,12 @@
+CREATE TABLE `webhooks` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`updated_at` datetime,`deleted_at` datetime,`user_id` integer,`url` text,`secret` text,`events` text,`active` numeric DEFAULT true,`failure_count` integer,`disabled_at` datetime,`disabled_reason` text);
+CREATE INDEX `idx_webhooks_user_id` ON `webhooks`(`user_id`);
+CREATE INDEX `idx_webhooks_deleted_at` ON `webhooks`(`deleted_at`);
+
+CREATE TABLE `webhook_deliveries` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`webhook_id` integer,`event_id` text,`event_type` text,`payload` text,`status` text,`attempts` integer,`next_attempt_at` datetime,`last_error` text,`delivered_at` datetime);
+CREATE INDEX `idx_webhook_deliveries_next_attempt_at` ON `webhook_deliveries`(`next_attempt_at`);
+CREATE INDEX `idx_webhook_deliveries_status` ON `webhook_deliveries`(`status`);
+CREATE UNIQUE INDEX `idx_webhook_deliveries_event_id` ON `webhook_deliveries`(`event_id`);
+CREATE INDEX `idx_webhook_deliveries_webhook_id` ON `webhook_deliveries`(`webhook_id`);
+
+CREATE TABLE `webhook_attempts` (`id` integer PRIMARY KEY AUTOINCREMENT,`created_at` datetime,`delivery_id` integer,`status_code` integer,`error` text,`duration_ms` integer,`response` text,CONSTRAINT `fk_webhook_deliveries_log` FOREIGN KEY (`delivery_id`) REFERENCES `webhook_deliveries`(`id`));
+CREATE INDEX `idx_webhook_attempts_delivery_id` ON `webhook_attempts`(`delivery_id`);
@@ -0,0 +1,26 @@
+package models
+
+import "gorm.io/gorm"
+
+// Comment komentar di todo, ParentID buat balasan (thread)
+type Comment struct {
+	gorm.Model
+	TodoID   uint   `json:"todo_id" gorm:"index"`
+	AuthorID uint   `json:"author_id"`
+	ParentID *uint  `json:"parent_id" gorm:"index"`
+	Body     string `json:"isi"`
+	Edited   bool   `json:"diedit"`
+	// Removed komentar yang dihapus tapi masih punya balasan, isinya dikosongkan
+	Removed bool `json:"dihapus"`
+}
+
+// Attachment file yang dilampirkan ke todo, isinya ada di storage
+type Attachment struct {
+	gorm.Model
+	TodoID      uint   `json:"todo_id" gorm:"index"`
+	UploaderID  uint   `json:"uploader_id"`
+	FileName    string `json:"nama_file"`
+	ContentType string `json:"content_type"`
+	Size        int64  `json:"ukuran"`
+	StorageKey  string `json:"-"`
+}
@@ -0,0 +1,75 @@
+package models
+
+import (
+	"regexp"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// Label buatan user, nempel ke banyak todo lewat tabel todo_labels.
+// Nama disimpan huruf kecil dan unik per user
+type Label struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	OwnerID   uint      `json:"owner_id" gorm:"uniqueIndex:idx_label_owner_name"`
+	Name      string    `json:"nama" gorm:"uniqueIndex:idx_label_owner_name"`
+	Color     string    `json:"warna"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TodoLabel baris tabel penghubung todo <-> label
+type TodoLabel struct {
+	TodoID  uint `gorm:"primaryKey"`
+	LabelID uint `gorm:"primaryKey;index"`
+}
+
+const DefaultLabelColor = "#9e9e9e"
+
+var colorPattern = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// ValidColor warna label format #rrggbb
+func ValidColor(color string) bool {
+	return colorPattern.MatchString(color)
+}
+
+// NormalizeLabelName nama label di-trim, spasi ganda dirapikan, huruf kecil
+func NormalizeLabelName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// LabelTodoIDs subquery id todo yang punya label apa pun dari labelIDs
+func LabelTodoIDs(db *gorm.DB, labelIDs []uint) *gorm.DB {
+	return db.Model(&TodoLabel{}).Select("todo_id").Where("label_id IN ?", labelIDs)
+}
+
+// MergeLabel pindahin semua todo dari label from ke label into lalu hapus from.
+// Todo yang sudah punya keduanya cukup disisakan satu
+func MergeLabel(db *gorm.DB, from, into uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		var links []TodoLabel
+		if err := tx.Where("label_id = ?", from).Find(&links).Error; err != nil {
+			return err
+		}
+		for i := range links {
+			links[i].LabelID = into
+		}
+		if len(links) > 0 {
+			if err := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links).Error; err != nil {
+				return err
+			}
+		}
+		return DeleteLabel(tx, from)
+	})
+}
+
+// DeleteLabel hapus label beserta semua tempelannya di todo
+func DeleteLabel(db *gorm.DB, id uint) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("label_id = ?", id).Delete(&TodoLabel{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&Label{}, id).Error
+	})
+}
@@ -0,0 +1,140 @@
+package models
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// role anggota list, urut dari yang paling sedikit haknya
+const (
+	ListViewer = "viewer"
+	ListEditor = "editor"
+	ListOwner  = "owner"
+)
+
+var listRoleRank = map[string]int{ListViewer: 1, ListEditor: 2, ListOwner: 3}
+
+func ValidListRole(role string) bool {
+	return listRoleRank[role] > 0
+}
+
+// RoleAtLeast true kalau role punya hak minimal need
+func RoleAtLeast(role, need string) bool {
+	return listRoleRank[role] >= listRoleRank[need] && listRoleRank[role] > 0
+}
+
+// List wadah todo (list/board) yang bisa dibagi ke user lain. Tiap user
+// punya satu list Personal yang dibuat otomatis
+type List struct {
+	gorm.Model
+	Name     string `json:"nama" binding:"required"`
+	OwnerID  uint   `json:"owner_id"`
+	Personal bool   `json:"personal"`
+}
+
+// ListMember keanggotaan user di list dengan role viewer/editor/owner
+type ListMember struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	ListID    uint      `json:"list_id" gorm:"uniqueIndex:idx_list_member"`
+	UserID    uint      `json:"user_id" gorm:"uniqueIndex:idx_list_member;index"`
+	Role      string    `json:"role"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+const (
+	InvitePending  = "pending"
+	InviteAccepted = "accepted"
+	InviteDeclined = "declined"
+)
+
+// ListInvite undangan gabung list, baru jadi anggota setelah diterima
+type ListInvite struct {
+	gorm.Model
+	ListID    uint   `json:"list_id" gorm:"index"`
+	InviterID uint   `json:"inviter_id"`
+	InviteeID uint   `json:"invitee_id" gorm:"index"`
+	Role      string `json:"role"`
+	Status    string `json:"status" gorm:"default:pending"`
+}
+
+// MemberRole role user di list, "" kalau bukan anggota
+func MemberRole(db *gorm.DB, listID, userID uint) string {
+	var m ListMember
+	if err := db.Where("list_id = ? AND user_id = ?", listID, userID).First(&m).Error; err != nil {
+		return ""
+	}
+	return m.Role
+}
+
+// MemberListIDs subquery id list yang user ikuti dengan role minimal need,
+// dipakai di semua query todo: Where("list_id IN (?)", MemberListIDs(...))
+func MemberListIDs(db *gorm.DB, userID uint, need string) *gorm.DB {
+	var roles []string
+	for role := range listRoleRank {
+		if RoleAtLeast(role, need) {
+			roles = append(roles, role)
+		}
+	}
+	return db.Model(&ListMember{}).Select("list_id").Where("user_id = ? AND role IN ?", userID, roles)
+}
+
+// ListMemberIDs semua anggota list, buat event dan notifikasi
+func ListMemberIDs(db *gorm.DB, listID uint) []uint {
+	var ids []uint
+	db.Model(&ListMember{}).Where("list_id = ?", listID).Pluck("user_id", &ids)
+	return ids
+}
+
+// TodoAudience user yang boleh lihat todo (anggota list-nya)
+func TodoAudience(db *gorm.DB, todo Todo) []uint {
+	ids := ListMemberIDs(db, todo.ListID)
+	if len(ids) == 0 {
+		ids = []uint{todo.UserID}
+	}
+	return ids
+}
+
+// CreateList bikin list dan jadikan pembuatnya owner
+func CreateList(db *gorm.DB, list *List) error {
+	return db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(list).Error; err != nil {
+			return err
+		}
+		return tx.Create(&ListMember{ListID: list.ID, UserID: list.OwnerID, Role: ListOwner}).Error
+	})
+}
+
+// PersonalList list Personal milik user, dibuat kalau belum ada
+func PersonalList(db *gorm.DB, userID uint) (List, error) {
+	var list List
+	err := db.Where("owner_id = ? AND personal = ?", userID, true).First(&list).Error
+	if err == nil {
+		return list, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return list, err
+	}
+	list = List{Name: "Personal", OwnerID: userID, Personal: true}
+	return list, CreateList(db, &list)
+}
+
+// BackfillLists todo lama yang belum punya list dipindah ke list Personal
+// pembuatnya. Aman dijalankan tiap start
+func BackfillLists(db *gorm.DB) error {
+	var userIDs []uint
+	if err := db.Model(&Todo{}).Where("list_id IS NULL OR list_id = 0").Distinct().Pluck("user_id", &userIDs).Error; err != nil {
+		return err
+	}
+	for _, userID := range userIDs {
+		list, err := PersonalList(db, userID)
+		if err != nil {
+			return err
+		}
+		if err := db.Model(&Todo{}).Where("user_id = ? AND (list_id IS NULL OR list_id = 0)", userID).Update("list_id", list.ID).Error; err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package models
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Reminder satu pengingat sebelum tenggat todo. RemindAt = DueAt - Offset,
+// SentAt diisi scheduler setelah notifikasinya dikirim
+type Reminder struct {
+	gorm.Model
+	TodoID        uint       `json:"todo_id" gorm:"index"`
+	OffsetMinutes int        `json:"menit_sebelum"`
+	RemindAt      time.Time  `json:"waktu" gorm:"index"`
+	SentAt        *time.Time `json:"terkirim,omitempty"`
+}
+
+// SyncReminders samakan pengingat todo dengan offset (menit sebelum tenggat).
+// offsets nil berarti offset lama dipakai lagi, cukup waktunya dihitung ulang
+// (misal tenggatnya digeser). Pengingat yang waktunya sudah lewat tidak
+// dikirim lagi, yang waktunya tidak berubah tetap ingat sudah terkirim
+func SyncReminders(db *gorm.DB, todo *Todo, offsets []int) error {
+	now := time.Now()
+	var existing []Reminder
+	if err := db.Where("todo_id = ?", todo.ID).Find(&existing).Error; err != nil {
+		return err
+	}
+	sent := map[int64]*time.Time{}
+	for _, r := range existing {
+		sent[r.RemindAt.Unix()] = r.SentAt
+	}
+	if offsets == nil {
+		for _, r := range existing {
+			offsets = append(offsets, r.OffsetMinutes)
+		}
+	}
+	if len(existing) > 0 {
+		if err := db.Unscoped().Where("todo_id = ?", todo.ID).Delete(&Reminder{}).Error; err != nil {
+			return err
+		}
+	}
+
+	// tanpa tenggat tidak ada yang bisa diingatkan
+	todo.Reminders = nil
+	todo.ReminderMinutes = nil
+	if todo.DueAt == nil {
+		return nil
+	}
+	seen := map[int]bool{}
+	for _, offset := range offsets {
+		if offset < 0 || seen[offset] {
+			continue
+		}
+		seen[offset] = true
+		todo.ReminderMinutes = append(todo.ReminderMinutes, offset)
+		r := Reminder{
+			TodoID:        todo.ID,
+			OffsetMinutes: offset,
+			RemindAt:      todo.DueAt.Add(-time.Duration(offset) * time.Minute),
+		}
+		if at, ok := sent[r.RemindAt.Unix()]; ok {
+			r.SentAt = at
+		} else if !r.RemindAt.After(now) {
+			r.SentAt = &now
+		}
+		todo.Reminders = append(todo.Reminders, r)
+	}
+	if len(todo.Reminders) == 0 {
+		return nil
+	}
+	return db.Create(&todo.Reminders).Error
+}
+
+// LoadReminderMinutes isi ReminderMinutes dari tabel reminders buat response
+func LoadReminderMinutes(db *gorm.DB, todo *Todo) {
+	todo.ReminderMinutes = nil
+	db.Model(&Reminder{}).Where("todo_id = ?", todo.ID).Order("offset_minutes desc").Pluck("offset_minutes", &todo.ReminderMinutes)
+}
@@ -0,0 +1,173 @@
+package models
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// defaultTransitions perpindahan status yang boleh kalau deployment tidak
+// ngatur sendiri lewat STATUS_TRANSITIONS
+var defaultTransitions = map[Status][]Status{
+	Pending:    {InProgress, Failed},
+	InProgress: {Pending, Success, Failed},
+	Success:    {InProgress},
+	Failed:     {Pending},
+}
+
+var transitions = defaultTransitions
+
+// LoadTransitions set aturan perpindahan dari JSON, misal
+// {"Pending": ["InProgress"], "InProgress": ["Success", "Failed"]}.
+// String kosong balik ke default
+func LoadTransitions(raw string) error {
+	if strings.TrimSpace(raw) == "" {
+		transitions = defaultTransitions
+		return nil
+	}
+	var parsed map[Status][]Status
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil {
+		return fmt.Errorf("STATUS_TRANSITIONS: %w", err)
+	}
+	for from, tos := range parsed {
+		if !ValidStatus(from) {
+			return fmt.Errorf("STATUS_TRANSITIONS: unknown status %q", from)
+		}
+		for _, to := range tos {
+			if !ValidStatus(to) {
+				return fmt.Errorf("STATUS_TRANSITIONS: unknown status %q", to)
+			}
+		}
+	}
+	transitions = parsed
+	return nil
+}
+
+func AllowedTransitions(from Status) []Status {
+	return transitions[from]
+}
+
+func CanTransition(from, to Status) bool {
+	for _, s := range transitions[from] {
+		if s == to {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusesThatCanBecome status asal yang boleh pindah ke to
+func StatusesThatCanBecome(to Status) []Status {
+	var out []Status
+	for from := range transitions {
+		if CanTransition(from, to) {
+			out = append(out, from)
+		}
+	}
+	return out
+}
+
+// StatusHistory satu perpindahan status. ActorID nil berarti sistem
+// (misal scheduler), From kosong berarti status awal waktu dibuat
+type StatusHistory struct {
+	ID        uint      `json:"id" gorm:"primarykey"`
+	TodoID    uint      `json:"todo_id" gorm:"index"`
+	From      Status    `json:"dari"`
+	To        Status    `json:"ke"`
+	ActorID   *uint     `json:"actor_id"`
+	Reason    string    `json:"alasan,omitempty"`
+	CreatedAt time.Time `json:"waktu"`
+}
+
+var (
+	ErrIllegalTransition = errors.New("illegal status transition")
+	ErrStatusChanged     = errors.New("status was changed by someone else, reload and try again")
+)
+
+// RecordStatus catat status (awal atau hasil update massal) ke history
+func RecordStatus(db *gorm.DB, todoID uint, from, to Status, actorID *uint, reason string) error {
+	return db.Create(&StatusHistory{TodoID: todoID, From: from, To: to, ActorID: actorID, Reason: reason}).Error
+}
+
+// ChangeStatus pindahkan status todo kalau aturannya ngebolehin, dan catat
+// ke history dalam satu transaksi. Update-nya bersyarat status lama, jadi
+// dua request barengan tidak bisa sama-sama lolos
+func ChangeStatus(db *gorm.DB, todo *Todo, to Status, actorID *uint, reason string) error {
+	from := todo.Status
+	if from == to {
+		return nil
+	}
+	if !CanTransition(from, to) {
+		return fmt.Errorf("%w: %s -> %s", ErrIllegalTransition, from, to)
+	}
+	return db.Transaction(func(tx *gorm.DB) error {
+		res := tx.Model(&Todo{}).Where("id = ? AND status = ?", todo.ID, from).Update("status", to)
+		if res.Error != nil {
+			return res.Error
+		}
+		if res.RowsAffected == 0 {
+			return ErrStatusChanged
+		}
+		if err := RecordStatus(tx, todo.ID, from, to, actorID, reason); err != nil {
+			return err
+		}
+		todo.Status = to
+		return nil
+	})
+}
+
+// CycleTimes ringkasan waktu dari history satu todo
+type CycleTimes struct {
+	// LeadTime dari dibuat sampai terakhir Success
+	LeadTime *time.Duration
+	// CycleTime dari pertama InProgress sampai terakhir Success
+	CycleTime *time.Duration
+	// TimeInStatus total lama di tiap status (status sekarang dihitung sampai now)
+	TimeInStatus map[Status]time.Duration
+}
+
+func ComputeCycleTimes(created time.Time, current Status, history []StatusHistory, now time.Time) CycleTimes {
+	ct := CycleTimes{TimeInStatus: map[Status]time.Duration{}}
+
+	// data lama belum punya entri awal, status awalnya diambil dari "dari"
+	// perpindahan pertama
+	var status Status
+	since := created
+	switch {
+	case len(history) == 0:
+		status = current
+	case history[0].From == "":
+		status = history[0].To
+		history = history[1:]
+	default:
+		status = history[0].From
+	}
+
+	var startedAt, doneAt *time.Time
+	for i := range history {
+		h := history[i]
+		ct.TimeInStatus[status] += h.CreatedAt.Sub(since)
+		status, since = h.To, h.CreatedAt
+		if h.To == InProgress && startedAt == nil {
+			startedAt = &history[i].CreatedAt
+		}
+		if h.To == Success {
+			doneAt = &history[i].CreatedAt
+		}
+	}
+	ct.TimeInStatus[status] += now.Sub(since)
+
+	if doneAt != nil && current == Success {
+		lead := doneAt.Sub(created)
+		ct.LeadTime = &lead
+		if startedAt != nil {
+			cycle := doneAt.Sub(*startedAt)
+			ct.CycleTime = &cycle
+		}
+	}
+	return ct
+}
@@ -0,0 +1,155 @@
+package models
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+	"todo/migrations"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func openDB(t *testing.T) *gorm.DB {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	if err := SetupJoinTables(db); err != nil {
+		t.Fatal(err)
+	}
+	return db
+}
+
+func resetTransitions(t *testing.T) {
+	t.Cleanup(func() { transitions = defaultTransitions })
+}
+
+func TestLoadTransitions(t *testing.T) {
+	resetTransitions(t)
+
+	if err := LoadTransitions(`{"Pending": ["Success"]}`); err != nil {
+		t.Fatal(err)
+	}
+	if !CanTransition(Pending, Success) || CanTransition(Pending, InProgress) || CanTransition(Success, InProgress) {
+		t.Fatalf("custom rules not applied: %v", transitions)
+	}
+
+	for _, raw := range []string{`{"Pending": [`, `{"Done": ["Pending"]}`, `{"Pending": ["Done"]}`} {
+		if err := LoadTransitions(raw); err == nil {
+			t.Errorf("LoadTransitions(%s) should fail", raw)
+		}
+	}
+	// yang gagal tidak ngubah aturan yang sudah ada
+	if !CanTransition(Pending, Success) {
+		t.Fatal("failed load replaced the rules")
+	}
+
+	if err := LoadTransitions("  "); err != nil {
+		t.Fatal(err)
+	}
+	if !CanTransition(Pending, InProgress) || CanTransition(Pending, Success) {
+		t.Fatal("empty string should restore the default rules")
+	}
+}
+
+func TestChangeStatus(t *testing.T) {
+	resetTransitions(t)
+	db := openDB(t)
+	todo := Todo{Title: "tulis laporan", Status: Pending}
+	if err := db.Create(&todo).Error; err != nil {
+		t.Fatal(err)
+	}
+	actor := uint(7)
+
+	if err := ChangeStatus(db, &todo, InProgress, &actor, "mulai"); err != nil {
+		t.Fatal(err)
+	}
+	if todo.Status != InProgress {
+		t.Fatalf("status = %s", todo.Status)
+	}
+
+	err := ChangeStatus(db, &todo, InProgress, nil, "")
+	if err != nil {
+		t.Fatalf("same status should be a no-op: %v", err)
+	}
+
+	todo.Status = Failed
+	if err := ChangeStatus(db, &todo, Success, nil, ""); !errors.Is(err, ErrIllegalTransition) {
+		t.Fatalf("Failed -> Success: err = %v", err)
+	}
+
+	// salinan basi: di database sudah InProgress
+	stale := todo
+	stale.Status = Pending
+	if err := ChangeStatus(db, &stale, Failed, nil, ""); !errors.Is(err, ErrStatusChanged) {
+		t.Fatalf("stale update: err = %v", err)
+	}
+	if stale.Status != Pending {
+		t.Fatal("stale copy should keep its status")
+	}
+
+	var history []StatusHistory
+	db.Where("todo_id = ?", todo.ID).Order("id").Find(&history)
+	if len(history) != 1 {
+		t.Fatalf("history = %+v", history)
+	}
+	h := history[0]
+	if h.From != Pending || h.To != InProgress || h.ActorID == nil || *h.ActorID != actor || h.Reason != "mulai" {
+		t.Fatalf("history entry = %+v", h)
+	}
+}
+
+func TestComputeCycleTimes(t *testing.T) {
+	created := time.Date(2024, 1, 1, 9, 0, 0, 0, time.UTC)
+	at := func(hours int) time.Time { return created.Add(time.Duration(hours) * time.Hour) }
+	history := []StatusHistory{
+		{From: "", To: Pending, CreatedAt: created},
+		{From: Pending, To: InProgress, CreatedAt: at(2)},
+		{From: InProgress, To: Pending, CreatedAt: at(3)},
+		{From: Pending, To: InProgress, CreatedAt: at(5)},
+		{From: InProgress, To: Success, CreatedAt: at(8)},
+	}
+
+	ct := ComputeCycleTimes(created, Success, history, at(10))
+	if ct.LeadTime == nil || *ct.LeadTime != 8*time.Hour {
+		t.Errorf("lead time = %v", ct.LeadTime)
+	}
+	if ct.CycleTime == nil || *ct.CycleTime != 6*time.Hour {
+		t.Errorf("cycle time = %v", ct.CycleTime)
+	}
+	want := map[Status]time.Duration{Pending: 4 * time.Hour, InProgress: 4 * time.Hour, Success: 2 * time.Hour}
+	for s, d := range want {
+		if ct.TimeInStatus[s] != d {
+			t.Errorf("time in %s = %v, want %v", s, ct.TimeInStatus[s], d)
+		}
+	}
+
+	// belum selesai: tidak ada lead / cycle time
+	ct = ComputeCycleTimes(created, InProgress, history[:2], at(4))
+	if ct.LeadTime != nil || ct.CycleTime != nil {
+		t.Errorf("unfinished todo has lead %v cycle %v", ct.LeadTime, ct.CycleTime)
+	}
+	if ct.TimeInStatus[InProgress] != 2*time.Hour {
+		t.Errorf("time in progress = %v", ct.TimeInStatus[InProgress])
+	}
+
+	// data lama tanpa entri awal, status awal dari "dari" perpindahan pertama
+	ct = ComputeCycleTimes(created, InProgress, []StatusHistory{{From: Pending, To: InProgress, CreatedAt: at(1)}}, at(3))
+	if ct.TimeInStatus[Pending] != time.Hour || ct.TimeInStatus[InProgress] != 2*time.Hour {
+		t.Errorf("legacy history = %v", ct.TimeInStatus)
+	}
+
+	ct = ComputeCycleTimes(created, Pending, nil, at(1))
+	if ct.TimeInStatus[Pending] != time.Hour {
+		t.Errorf("no history = %v", ct.TimeInStatus)
+	}
+}
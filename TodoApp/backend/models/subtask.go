@@ -0,0 +1,12 @@
+package models
+
+import "gorm.io/gorm"
+
+// Subtask langkah kecil di bawah satu todo, statusnya sendiri-sendiri
+type Subtask struct {
+	gorm.Model
+	TodoID   uint   `json:"todo_id" gorm:"index"`
+	Title    string `json:"judul" binding:"required"`
+	Status   Status `json:"status" gorm:"default:Pending"`
+	Position int    `json:"urutan"`
+}
@@ -2,7 +2,6 @@ package models
 
 import (
 	"time"
-	"todo/config"
 
 	"gorm.io/gorm"
 )
@@ -16,15 +15,59 @@ const (
 	Success    Status = "Success"
 )
 
+type Priority string
+
+const (
+	PriorityLow    Priority = "low"
+	PriorityNormal Priority = "normal"
+	PriorityHigh   Priority = "high"
+	PriorityUrgent Priority = "urgent"
+)
+
+func ValidPriority(p Priority) bool {
+	return p == PriorityLow || p == PriorityNormal || p == PriorityHigh || p == PriorityUrgent
+}
+
+// DescStatus asal deskripsi todo: ditulis user (manual) atau dibuat AI di
+// background (pending -> generated / failed)
+type DescStatus string
+
+const (
+	DescPending   DescStatus = "pending"
+	DescGenerated DescStatus = "generated"
+	DescFailed    DescStatus = "failed"
+	DescManual    DescStatus = "manual"
+)
+
+// Timestamp (Tanggal) waktu todo dicatat, tenggat ada di DueAt
 type Todo struct {
 	gorm.Model
 	Title  string `json:"judul" binding:"required"`
 	Desc   string `json:"deskripsi"`
+	DescStatus DescStatus `json:"deskripsi_status" gorm:"default:manual"`
+	DescError string `json:"deskripsi_error,omitempty"`
 	Timestamp time.Time`json:"Tanggal"`
 	Status Status `json:"status" gorm:"default:Pending"`
+	Priority Priority `json:"prioritas" gorm:"default:normal"`
+	DueAt *time.Time `json:"tenggat,omitempty" gorm:"index"`
+	// ReminderMinutes input/output pengingat dalam menit sebelum tenggat,
+	// disimpan di tabel reminders
+	ReminderMinutes []int `json:"pengingat_menit,omitempty" gorm:"-"`
+	Reminders []Reminder `json:"-" gorm:"foreignKey:TodoID"`
+	// UserID pembuat todo, akses diatur lewat keanggotaan list
 	UserID uint `json:"user_id"`
+	ListID uint `json:"list_id" gorm:"index"`
+	AssigneeID *uint `json:"assignee_id"`
+	Subtasks []Subtask `json:"subtasks,omitempty" gorm:"foreignKey:TodoID"`
+	Labels []Label `json:"labels,omitempty" gorm:"many2many:todo_labels"`
+}
+
+func ValidStatus(s Status) bool {
+	return s == Pending || s == InProgress || s == Failed || s == Success
 }
 
-func AutoMigrate() {
-	config.DB.AutoMigrate(&Todo{})
+// SetupJoinTables daftarkan model tabel penghubung ke GORM, dipanggil
+// sekali setelah koneksi dibuka. Skemanya sendiri diatur package migrations
+func SetupJoinTables(db *gorm.DB) error {
+	return db.SetupJoinTable(&Todo{}, "Labels", &TodoLabel{})
 }
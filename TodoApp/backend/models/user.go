@@ -1,17 +1,55 @@
 package models
 
 import (
+		"errors"
+		"log"
+
+		"golang.org/x/crypto/bcrypt"
 		"gorm.io/gorm"
 		"todo/config"
 )
 
+// role user, disimpan di tabel users dan dibawa di JWT
+const (
+	RoleUser  = "user"
+	RoleAdmin = "admin"
+)
+
 type User struct {
 	gorm.Model
 	Username string `json:"Username" binding:"required" gorm:"unique"`
 	Password string `json:"Password" binding:"required"`
+	Role string `json:"Role" gorm:"default:user"`
+	Disabled bool `json:"Disabled"`
 	Todos []Todo `json:"Todos" gorm:"foreginKey:UserID"`
 }
 
-func AutoMigrateUser() {
-	config.DB.AutoMigrate(&User{})
+// BootstrapAdmin jadikan username admin pertama. Kalau usernya belum ada dan
+// password dikasih, user baru dibuat langsung sebagai admin
+func BootstrapAdmin(username, password string) error {
+	var user User
+	err := config.DB.Where("username = ?", username).First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if password == "" {
+			return errors.New("user " + username + " not found, set ADMIN_PASSWORD to create it")
+		}
+		hashPw, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+		if err != nil {
+			return err
+		}
+		user = User{Username: username, Password: string(hashPw), Role: RoleAdmin}
+		if err := config.DB.Create(&user).Error; err != nil {
+			return err
+		}
+		log.Println("admin dibuat:", username)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if user.Role == RoleAdmin && !user.Disabled {
+		return nil
+	}
+	log.Println("user dijadikan admin:", username)
+	return config.DB.Model(&user).Updates(map[string]interface{}{"role": RoleAdmin, "disabled": false}).Error
 }
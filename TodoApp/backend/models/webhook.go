@@ -0,0 +1,104 @@
+package models
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// event yang bisa dilanggan webhook, sama dengan tipe event di package events
+var WebhookEvents = []string{"todo.created", "todo.updated", "todo.status_changed", "todo.deleted", "todo.moved", "todo.reminder", "todo.overdue"}
+
+// Webhook URL milik user yang dikirimi event todo dari semua list yang dia
+// ikuti. Secret dipakai buat tanda tangan HMAC-SHA256 tiap kiriman
+type Webhook struct {
+	gorm.Model
+	UserID uint   `json:"user_id" gorm:"index"`
+	URL    string `json:"url"`
+	Secret string `json:"-"`
+	// Events dipisah koma, kosong artinya semua event
+	Events string `json:"events"`
+	Active bool   `json:"aktif" gorm:"default:true"`
+	// FailureCount kiriman gagal berturut-turut, balik ke 0 kalau ada yang sukses
+	FailureCount   int        `json:"gagal_beruntun"`
+	DisabledAt     *time.Time `json:"dimatikan_pada,omitempty"`
+	DisabledReason string     `json:"alasan_dimatikan,omitempty"`
+}
+
+// status WebhookDelivery
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryFailed    = "failed"
+)
+
+// WebhookDelivery satu event yang harus dikirim ke satu webhook (outbox).
+// Baris ini ditulis dulu, dikirim worker, dan dicoba ulang sampai berhasil
+// atau jatah percobaannya habis
+type WebhookDelivery struct {
+	ID            uint             `json:"id" gorm:"primarykey"`
+	CreatedAt     time.Time        `json:"created_at"`
+	WebhookID     uint             `json:"webhook_id" gorm:"index"`
+	EventID       string           `json:"event_id" gorm:"uniqueIndex"`
+	EventType     string           `json:"event"`
+	Payload       string           `json:"payload"`
+	Status        string           `json:"status" gorm:"index"`
+	Attempts      int              `json:"percobaan"`
+	NextAttemptAt time.Time        `json:"percobaan_berikutnya" gorm:"index"`
+	LastError     string           `json:"error_terakhir,omitempty"`
+	DeliveredAt   *time.Time       `json:"terkirim_pada,omitempty"`
+	Log           []WebhookAttempt `json:"log,omitempty" gorm:"foreignKey:DeliveryID"`
+}
+
+// WebhookAttempt log tiap percobaan kirim, buat debug dari sisi user
+type WebhookAttempt struct {
+	ID         uint      `json:"id" gorm:"primarykey"`
+	CreatedAt  time.Time `json:"created_at"`
+	DeliveryID uint      `json:"delivery_id" gorm:"index"`
+	StatusCode int       `json:"status_code,omitempty"`
+	Error      string    `json:"error,omitempty"`
+	DurationMs int64     `json:"durasi_ms"`
+	// Response potongan awal body balasan receiver
+	Response string `json:"response,omitempty"`
+}
+
+func ValidWebhookEvent(event string) bool {
+	for _, e := range WebhookEvents {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// Wants true kalau webhook melanggan event ini
+func (w Webhook) Wants(event string) bool {
+	if !ValidWebhookEvent(event) {
+		return false
+	}
+	if strings.TrimSpace(w.Events) == "" {
+		return true
+	}
+	for _, e := range strings.Split(w.Events, ",") {
+		if strings.TrimSpace(e) == event {
+			return true
+		}
+	}
+	return false
+}
+
+// NewWebhookSecret secret acak 32 byte dalam hex, ditampilkan ke user sekali
+// waktu webhook dibuat atau secret-nya diganti
+func NewWebhookSecret() string {
+	return "whsec_" + RandomID(32)
+}
+
+// RandomID hex acak sepanjang n byte
+func RandomID(n int) string {
+	b := make([]byte, n)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
@@ -3,6 +3,7 @@ package routes
 import (
 	"todo/controller"
 	"todo/middleware"
+	"todo/models"
 
 	"github.com/gin-gonic/gin"
 )
@@ -10,14 +11,75 @@ import (
 func TodoRoutes(r *gin.Engine) {
 	r.POST("/register", controller.Register)
 	r.POST("/login", controller.Login)
+	// EventSource tidak bisa kirim header, token boleh lewat ?token=
+	r.GET("/todos/stream", middleware.TokenFromQuery(), middleware.AuthMiddleware(), controller.StreamTodos)
+	// link download bertanda tangan dari GET /todo/:id/attachments/:attID/url
+	r.GET("/files/:attID", controller.ServeSignedFile)
+
 	auth := r.Group("/")
 	auth.Use(middleware.AuthMiddleware())
 	{
-		auth.GET("/todos", controller.GetAllTodo)
+		auth.GET("/todos", middleware.RequireRole(models.RoleAdmin), controller.GetAllTodo)
 		auth.GET("/todos/my", controller.GetMyTodos)
 		auth.POST("/todo", controller.CreateTodo)
+		auth.POST("/todo/parse", controller.ParseTodo)
+		auth.GET("/todo/:id", controller.GetTodo)
+		auth.POST("/todo/:id/description", controller.RegenerateDescription)
+		auth.GET("/todo/:id/subtasks", controller.GetSubtasks)
+		auth.POST("/todo/:id/subtasks", controller.CreateSubtasks)
+		auth.POST("/todo/:id/subtasks/suggest", controller.SuggestSubtasks)
+		auth.PUT("/todo/:id/subtasks/:subID", controller.UpdateSubtask)
+		auth.DELETE("/todo/:id/subtasks/:subID", controller.DeleteSubtask)
 		auth.PUT("/todo/:id/status", controller.UpdateStatus)
+		auth.GET("/todo/:id/history", controller.GetStatusHistory)
 		auth.PUT("/todo/:id/update", controller.UpdateTodo)
+		auth.PUT("/todo/:id/assignee", controller.AssignTodo)
 		auth.DELETE("/todo/:id/delete", controller.DeleteTodo)
+		auth.POST("/todo/:id/labels/:labelID", controller.AddTodoLabel)
+		auth.DELETE("/todo/:id/labels/:labelID", controller.RemoveTodoLabel)
+		auth.GET("/todo/:id/comments", controller.GetComments)
+		auth.POST("/todo/:id/comments", controller.CreateComment)
+		auth.PUT("/todo/:id/comments/:commentID", controller.UpdateComment)
+		auth.DELETE("/todo/:id/comments/:commentID", controller.DeleteComment)
+		auth.GET("/todo/:id/attachments", controller.GetAttachments)
+		auth.POST("/todo/:id/attachments", controller.UploadAttachment)
+		auth.GET("/todo/:id/attachments/:attID", controller.DownloadAttachment)
+		auth.GET("/todo/:id/attachments/:attID/url", controller.AttachmentURL)
+		auth.DELETE("/todo/:id/attachments/:attID", controller.DeleteAttachment)
+
+		auth.GET("/lists", controller.GetLists)
+		auth.POST("/lists", controller.CreateList)
+		auth.PUT("/lists/:listID", controller.RenameList)
+		auth.DELETE("/lists/:listID", controller.DeleteList)
+		auth.GET("/lists/:listID/members", controller.GetListMembers)
+		auth.PUT("/lists/:listID/members/:userID", controller.UpdateListMember)
+		auth.DELETE("/lists/:listID/members/:userID", controller.RemoveListMember)
+		auth.POST("/lists/:listID/invites", controller.InviteToList)
+		auth.GET("/labels", controller.GetLabels)
+		auth.POST("/labels", controller.CreateLabel)
+		auth.PUT("/labels/:labelID", controller.UpdateLabel)
+		auth.DELETE("/labels/:labelID", controller.DeleteLabel)
+		auth.POST("/labels/:labelID/merge", controller.MergeLabel)
+		auth.GET("/webhooks", controller.GetWebhooks)
+		auth.POST("/webhooks", controller.CreateWebhook)
+		auth.GET("/webhooks/:hookID", controller.GetWebhook)
+		auth.PUT("/webhooks/:hookID", controller.UpdateWebhook)
+		auth.DELETE("/webhooks/:hookID", controller.DeleteWebhook)
+		auth.POST("/webhooks/:hookID/secret", controller.RotateWebhookSecret)
+		auth.POST("/webhooks/:hookID/ping", controller.PingWebhook)
+		auth.GET("/webhooks/:hookID/deliveries", controller.GetWebhookDeliveries)
+		auth.POST("/webhooks/:hookID/deliveries/:deliveryID/redeliver", controller.RedeliverWebhook)
+		auth.GET("/invites", controller.GetMyInvites)
+		auth.POST("/invites/:inviteID/accept", controller.AcceptInvite)
+		auth.POST("/invites/:inviteID/decline", controller.DeclineInvite)
+	}
+
+	admin := r.Group("/admin")
+	admin.Use(middleware.AuthMiddleware(), middleware.RequireRole(models.RoleAdmin))
+	{
+		admin.GET("/users", controller.ListUsers)
+		admin.PUT("/users/:id/disable", controller.SetUserDisabled)
+		admin.GET("/users/:id/todos", controller.GetUserTodos)
+		admin.GET("/stats", controller.GetStats)
 	}
 }
\ No newline at end of file
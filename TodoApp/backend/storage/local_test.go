@@ -0,0 +1,64 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalRejectsBadKeys(t *testing.T) {
+	root := t.TempDir()
+	l, err := NewLocal(filepath.Join(root, "uploads"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	for _, key := range []string{"", "..", "../secret", "a/../../secret", "todos/1/../../../etc/passwd"} {
+		if err := l.Put(ctx, key, strings.NewReader("x"), 1, "text/plain"); err == nil {
+			t.Errorf("Put(%q) should fail", key)
+		}
+		if _, err := l.Get(ctx, key); err == nil {
+			t.Errorf("Get(%q) should fail", key)
+		}
+		if err := l.Delete(ctx, key); err == nil {
+			t.Errorf("Delete(%q) should fail", key)
+		}
+	}
+	if entries, _ := os.ReadDir(root); len(entries) != 1 {
+		t.Fatalf("files written outside the storage root: %v", entries)
+	}
+}
+
+func TestLocalPutGetDelete(t *testing.T) {
+	l, err := NewLocal(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+	key := "todos/1/catatan.txt"
+	if err := l.Put(ctx, key, strings.NewReader("halo"), 4, "text/plain"); err != nil {
+		t.Fatal(err)
+	}
+	rc, err := l.Get(ctx, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(rc)
+	rc.Close()
+	if string(data) != "halo" {
+		t.Fatalf("Get = %q", data)
+	}
+	if err := l.Delete(ctx, key); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := l.Get(ctx, key); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("Get after delete: err = %v", err)
+	}
+	if err := l.Delete(ctx, key); err != nil {
+		t.Fatalf("deleting a missing key should be fine: %v", err)
+	}
+}
@@ -0,0 +1,57 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// Storage tempat nyimpen isi file lampiran. Key dibuat server, bukan dari
+// nama file user
+type Storage interface {
+	Put(ctx context.Context, key string, r io.Reader, size int64, contentType string) error
+	Get(ctx context.Context, key string) (io.ReadCloser, error)
+	Delete(ctx context.Context, key string) error
+}
+
+var ErrNotFound = errors.New("file not found")
+
+// Default storage yang dipakai controller, diisi Init
+var Default Storage
+
+// Config diisi main dari config.StorageConfig. Driver local (default) atau
+// s3; Endpoint misal http://localhost:9000 (MinIO) atau https://s3.amazonaws.com
+type Config struct {
+	Driver    string
+	Dir       string
+	Endpoint  string
+	Bucket    string
+	Region    string
+	AccessKey string
+	SecretKey string
+}
+
+func Init(cfg Config) error {
+	switch cfg.Driver {
+	case "", "local":
+		dir := cfg.Dir
+		if dir == "" {
+			dir = "uploads"
+		}
+		s, err := NewLocal(dir)
+		if err != nil {
+			return err
+		}
+		Default = s
+	case "s3":
+		s, err := NewS3(cfg)
+		if err != nil {
+			return err
+		}
+		Default = s
+	default:
+		return fmt.Errorf("unknown STORAGE_DRIVER %q", cfg.Driver)
+	}
+	return nil
+}
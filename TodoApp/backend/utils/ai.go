@@ -2,52 +2,205 @@ package utils
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
-	"os"
+	"strings"
+	"time"
+)
+
+// AIProvider backend AI yang dipakai buat generate teks. Implementasinya
+// ada di ai_gemini.go, ai_openai.go dan ai_stub.go
+type AIProvider interface {
+	Name() string
+	Generate(ctx context.Context, req AIRequest) (string, error)
+}
+
+// AIRequest satu permintaan ke AI. Provider asli cukup pakai Prompt, stub
+// pakai Task dan Input biar hasilnya bisa ditebak
+type AIRequest struct {
+	Task   string
+	Input  string
+	Prompt string
+}
+
+const (
+	taskDescription = "description"
+	taskBreakdown   = "breakdown"
+	taskParse       = "parse"
+)
+
+// ErrAIDisabled dikembalikan kalau server jalan tanpa AI
+var ErrAIDisabled = errors.New("AI is disabled")
 
-	"github.com/google/generative-ai-go/genai"
-	"google.golang.org/api/option"
+var (
+	aiProvider AIProvider
+	aiTimeout  = 20 * time.Second
 )
 
-var aiClient *genai.Client
+// AIConfig pengaturan provider AI, diisi main dari config.AIConfig
+type AIConfig struct {
+	Provider string
+	Model    string
+	BaseURL  string
+	APIKey   string
+	Timeout  time.Duration
+}
+
+// SetupAI pilih provider. Kalau gagal server tetap jalan, fitur AI saja
+// yang mati
+func SetupAI(cfg AIConfig) error {
+	aiProvider = nil
+	if cfg.Timeout > 0 {
+		aiTimeout = cfg.Timeout
+	}
+
+	var (
+		p   AIProvider
+		err error
+	)
+	switch cfg.Provider {
+	case "none", "":
+		return nil
+	case "gemini":
+		p, err = newGeminiProvider(cfg)
+	case "openai":
+		p, err = newOpenAIProvider(cfg)
+	case "stub":
+		p = stubProvider{}
+	default:
+		err = fmt.Errorf("unknown AI provider %q", cfg.Provider)
+	}
+	if err != nil {
+		return err
+	}
+	aiProvider = p
+	return nil
+}
+
+func AIEnabled() bool {
+	return aiProvider != nil
+}
+
+func AIProviderName() string {
+	if aiProvider == nil {
+		return "none"
+	}
+	return aiProvider.Name()
+}
 
-func InitAI() {
-	ctx := context.Background()
-	apiKey := os.Getenv("GEMINI_API_KEY")
-	if apiKey == "" {
-		panic("GEMINI_API_KEY not set")
+// generate panggil provider dengan batas waktu aiTimeout
+func generate(ctx context.Context, req AIRequest) (string, error) {
+	if aiProvider == nil {
+		return "", ErrAIDisabled
 	}
+	ctx, cancel := context.WithTimeout(ctx, aiTimeout)
+	defer cancel()
 
-	client, err := genai.NewClient(ctx, option.WithAPIKey(apiKey))
+	result, err := aiProvider.Generate(ctx, req)
 	if err != nil {
-		panic(err)
+		return "", fmt.Errorf("%s: %w", aiProvider.Name(), err)
+	}
+	result = strings.TrimSpace(result)
+	if result == "" {
+		return "", fmt.Errorf("%s: no response from AI", aiProvider.Name())
 	}
-	aiClient = client
+	return result, nil
 }
-func GenerateDescription(title string) (string, error) {
-	ctx := context.Background()
-	model := aiClient.GenerativeModel("gemini-1.5-flash")
 
+func GenerateDescription(ctx context.Context, title string) (string, error) {
 	prompt := fmt.Sprintf(
 		"Buat deskripsi singkat dan relevan untuk todo dengan judul: \"%s\". Jangan kasih pertanyaan balik, cukup 1-2 kalimat jelas dan juga kasih emoji supara interaktif.",
 		title,
 	)
+	return generate(ctx, AIRequest{Task: taskDescription, Input: title, Prompt: prompt})
+}
+
+const (
+	minSteps = 3
+	maxSteps = 8
+)
 
-	resp, err := model.GenerateContent(ctx, genai.Text(prompt))
+// BreakdownTodo minta AI memecah todo jadi 3-8 langkah konkret
+func BreakdownTodo(ctx context.Context, title, desc string) ([]string, error) {
+	prompt := fmt.Sprintf(
+		"Pecah todo berikut menjadi %d sampai %d langkah konkret yang bisa langsung dikerjakan, urut dari awal sampai selesai.\n"+
+			"Judul: \"%s\"\nDeskripsi: \"%s\"\n"+
+			"Jawab HANYA dengan array JSON berisi string, satu string per langkah, tanpa penjelasan lain. Contoh: [\"langkah 1\", \"langkah 2\", \"langkah 3\"]",
+		minSteps, maxSteps, title, desc,
+	)
+	result, err := generate(ctx, AIRequest{Task: taskBreakdown, Input: title, Prompt: prompt})
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+
+	steps := parseSteps(result)
+	if len(steps) < minSteps {
+		return nil, fmt.Errorf("AI returned %d steps, expected at least %d", len(steps), minSteps)
+	}
+	if len(steps) > maxSteps {
+		steps = steps[:maxSteps]
 	}
+	return steps, nil
+}
 
-	if len(resp.Candidates) == 0 {
-		return "", fmt.Errorf("no response from AI")
+// parseSteps baca array JSON dari jawaban AI. Model sering nambah ```json
+// atau malah jawab pakai daftar bernomor, dua-duanya tetap diterima
+func parseSteps(text string) []string {
+	text = strings.TrimSpace(text)
+	if start, end := strings.Index(text, "["), strings.LastIndex(text, "]"); start >= 0 && end > start {
+		var steps []string
+		if err := json.Unmarshal([]byte(text[start:end+1]), &steps); err == nil {
+			return cleanSteps(steps)
+		}
 	}
 
-	var result string
-	for _, part := range resp.Candidates[0].Content.Parts {
-		if txt, ok := part.(genai.Text); ok {
-			result += string(txt)
+	var steps []string
+	for _, line := range strings.Split(text, "\n") {
+		line = strings.TrimSpace(line)
+		line = strings.TrimLeft(line, "-*•0123456789.) ")
+		if line != "" && !strings.HasPrefix(line, "```") {
+			steps = append(steps, line)
 		}
 	}
+	return cleanSteps(steps)
+}
 
-	return result, nil
+func cleanSteps(steps []string) []string {
+	var out []string
+	for _, s := range steps {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+// ParseTodoAI minta AI ngubah teks bebas jadi todo. now dipakai buat
+// ngartiin "besok", "jumat" dan sejenisnya
+func ParseTodoAI(ctx context.Context, text string, now time.Time) (ParsedTodo, error) {
+	prompt := fmt.Sprintf(
+		"Sekarang %s (%s). Ubah catatan berikut menjadi todo: \"%s\"\n"+
+			"Jawab HANYA dengan objek JSON tanpa penjelasan lain, formatnya:\n"+
+			`{"judul": "judul singkat tanpa keterangan waktu/prioritas", "deskripsi": "1 kalimat", "tenggat": "RFC3339 atau null", "prioritas": "low|normal|high|urgent", "status": "Pending|InProgress|Success|Failed"}`,
+		now.Format(time.RFC3339), now.Weekday(), text,
+	)
+	result, err := generate(ctx, AIRequest{Task: taskParse, Input: text, Prompt: prompt})
+	if err != nil {
+		return ParsedTodo{}, err
+	}
+
+	start, end := strings.Index(result, "{"), strings.LastIndex(result, "}")
+	if start < 0 || end < start {
+		return ParsedTodo{}, errors.New("AI did not return JSON")
+	}
+	var parsed ParsedTodo
+	if err := json.Unmarshal([]byte(result[start:end+1]), &parsed); err != nil {
+		return ParsedTodo{}, fmt.Errorf("invalid JSON from AI: %w", err)
+	}
+	parsed.Title = strings.TrimSpace(parsed.Title)
+	if parsed.Title == "" {
+		return ParsedTodo{}, errors.New("AI returned an empty title")
+	}
+	return parsed, nil
 }
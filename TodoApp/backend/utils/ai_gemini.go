@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"errors"
+
+	"github.com/google/generative-ai-go/genai"
+	"google.golang.org/api/option"
+)
+
+type geminiProvider struct {
+	client *genai.Client
+	model  string
+}
+
+func newGeminiProvider(cfg AIConfig) (AIProvider, error) {
+	if cfg.APIKey == "" {
+		return nil, errors.New("GEMINI_API_KEY not set")
+	}
+	client, err := genai.NewClient(context.Background(), option.WithAPIKey(cfg.APIKey))
+	if err != nil {
+		return nil, err
+	}
+	model := cfg.Model
+	if model == "" {
+		model = "gemini-1.5-flash"
+	}
+	return &geminiProvider{client: client, model: model}, nil
+}
+
+func (g *geminiProvider) Name() string {
+	return "gemini"
+}
+
+func (g *geminiProvider) Generate(ctx context.Context, req AIRequest) (string, error) {
+	resp, err := g.client.GenerativeModel(g.model).GenerateContent(ctx, genai.Text(req.Prompt))
+	if err != nil {
+		return "", err
+	}
+	if len(resp.Candidates) == 0 || resp.Candidates[0].Content == nil {
+		return "", errors.New("no response from AI")
+	}
+
+	var result string
+	for _, part := range resp.Candidates[0].Content.Parts {
+		if txt, ok := part.(genai.Text); ok {
+			result += string(txt)
+		}
+	}
+	return result, nil
+}
@@ -0,0 +1,38 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// stubProvider AI palsu buat dev dan test: tanpa jaringan, hasilnya selalu
+// sama untuk input yang sama
+type stubProvider struct{}
+
+func (stubProvider) Name() string {
+	return "stub"
+}
+
+func (stubProvider) Generate(ctx context.Context, req AIRequest) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	switch req.Task {
+	case taskDescription:
+		return fmt.Sprintf("Kerjakan \"%s\" sampai selesai ✅", req.Input), nil
+	case taskBreakdown:
+		steps, _ := json.Marshal([]string{
+			"Siapkan kebutuhan untuk " + req.Input,
+			"Kerjakan " + req.Input,
+			"Cek hasil " + req.Input,
+		})
+		return string(steps), nil
+	case taskParse:
+		parsed, _ := json.Marshal(ParseTodoRules(req.Input, time.Now()))
+		return string(parsed), nil
+	default:
+		return "", fmt.Errorf("stub: unknown task %q", req.Task)
+	}
+}
@@ -6,19 +6,33 @@ import (
 	"github.com/golang-jwt/jwt/v5"
 )
 
-var jwtkey = []byte("anjay")
+var (
+	jwtkey   []byte
+	tokenTTL = 24 * time.Hour
+)
+
+// SetupJWT set secret buat tanda tangan token (dan link download) serta
+// umur token, dipanggil main dari config
+func SetupJWT(secret string, ttl time.Duration) {
+	jwtkey = []byte(secret)
+	if ttl > 0 {
+		tokenTTL = ttl
+	}
+}
 
 type Claims struct {
 	UserID uint `json:"user_id"`
 	Username string `json:"username"`
+	Role string `json:"role"`
 	jwt.RegisteredClaims
 }
 
-func GenerateToken(userID uint, username string) (string, error) {
-	expiredAt := time.Now().Add(24 * time.Hour)
+func GenerateToken(userID uint, username string, role string) (string, error) {
+	expiredAt := time.Now().Add(tokenTTL)
 	jwtToken := &Claims{
 		UserID: userID,
 		Username: username,
+		Role: role,
 		RegisteredClaims: jwt.RegisteredClaims{
 			ExpiresAt: jwt.NewNumericDate(expiredAt),
 		},
@@ -0,0 +1,176 @@
+package utils
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+)
+
+// ParsedTodo usulan todo dari satu baris teks bebas
+type ParsedTodo struct {
+	Title    string     `json:"judul"`
+	Desc     string     `json:"deskripsi"`
+	DueAt    *time.Time `json:"tenggat"`
+	Priority string     `json:"prioritas"`
+	Status   string     `json:"status"`
+}
+
+var (
+	rePriority = regexp.MustCompile(`(?i)\b(?:prioritas\s+)?(urgent|penting|segera|asap|tinggi|high|rendah|low|santai)\b|!{2,}`)
+	reStatus   = regexp.MustCompile(`(?i)\b(sedang dikerjakan|lagi dikerjakan|in progress|sudah selesai|selesai|done)\b`)
+	reISODate  = regexp.MustCompile(`\b(\d{4})-(\d{2})-(\d{2})\b`)
+	reDMY      = regexp.MustCompile(`\b(?:tgl\s+|tanggal\s+)?(\d{1,2})/(\d{1,2})(?:/(\d{4}))?\b`)
+	reDay      = regexp.MustCompile(`(?i)\b(?:hari\s+|on\s+)?(hari ini|today|besok|tomorrow|lusa|senin|selasa|rabu|kamis|jumat|jum'at|sabtu|minggu|monday|tuesday|wednesday|thursday|friday|saturday|sunday)\b`)
+	reClock    = regexp.MustCompile(`(?i)\b(?:jam|pukul|at)\s+(\d{1,2})(?:[:.](\d{2}))?(?:\s*(pagi|siang|sore|malam|am|pm))?\b|\b(\d{1,2})(?:[:.](\d{2}))?\s*(am|pm)\b`)
+)
+
+var dayNames = map[string]time.Weekday{
+	"minggu": time.Sunday, "sunday": time.Sunday,
+	"senin": time.Monday, "monday": time.Monday,
+	"selasa": time.Tuesday, "tuesday": time.Tuesday,
+	"rabu": time.Wednesday, "wednesday": time.Wednesday,
+	"kamis": time.Thursday, "thursday": time.Thursday,
+	"jumat": time.Friday, "jum'at": time.Friday, "friday": time.Friday,
+	"sabtu": time.Saturday, "saturday": time.Saturday,
+}
+
+// ParseTodoRules parser tanpa AI, ngerti bahasa Indonesia dan Inggris
+// sederhana: "bayar listrik jumat jam 5 sore, urgent" jadi judul "Bayar
+// listrik", tenggat Jumat 17:00, prioritas urgent
+func ParseTodoRules(text string, now time.Time) ParsedTodo {
+	parsed := ParsedTodo{Priority: "normal", Status: "Pending"}
+	rest := text
+
+	// cut hapus bagian yang sudah dikenali dari judul
+	cut := func(re *regexp.Regexp) []string {
+		loc := re.FindStringSubmatchIndex(rest)
+		if loc == nil {
+			return nil
+		}
+		groups := make([]string, len(loc)/2)
+		for i := range groups {
+			if loc[2*i] >= 0 {
+				groups[i] = rest[loc[2*i]:loc[2*i+1]]
+			}
+		}
+		rest = rest[:loc[0]] + " " + rest[loc[1]:]
+		return groups
+	}
+
+	if m := cut(rePriority); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "urgent", "segera", "asap", "":
+			parsed.Priority = "urgent"
+		case "penting", "tinggi", "high":
+			parsed.Priority = "high"
+		case "rendah", "low", "santai":
+			parsed.Priority = "low"
+		}
+	}
+	if m := cut(reStatus); m != nil {
+		switch strings.ToLower(m[1]) {
+		case "sudah selesai", "selesai", "done":
+			parsed.Status = "Success"
+		default:
+			parsed.Status = "InProgress"
+		}
+	}
+
+	var day *time.Time
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if m := cut(reISODate); m != nil {
+		if d, err := time.ParseInLocation("2006-01-02", m[0], now.Location()); err == nil {
+			day = &d
+		}
+	} else if m := cut(reDMY); m != nil {
+		dd, _ := strconv.Atoi(m[1])
+		mm, _ := strconv.Atoi(m[2])
+		yy := now.Year()
+		if m[3] != "" {
+			yy, _ = strconv.Atoi(m[3])
+		}
+		d := time.Date(yy, time.Month(mm), dd, 0, 0, 0, 0, now.Location())
+		// tanpa tahun dan sudah lewat → tahun depan
+		if m[3] == "" && d.Before(today) {
+			d = d.AddDate(1, 0, 0)
+		}
+		day = &d
+	} else if m := cut(reDay); m != nil {
+		d := today
+		switch word := strings.ToLower(m[1]); word {
+		case "hari ini", "today":
+		case "besok", "tomorrow":
+			d = d.AddDate(0, 0, 1)
+		case "lusa":
+			d = d.AddDate(0, 0, 2)
+		default:
+			diff := (int(dayNames[word]) - int(today.Weekday()) + 7) % 7
+			d = d.AddDate(0, 0, diff)
+		}
+		day = &d
+	}
+
+	hour, minute, hasClock := -1, 0, false
+	if m := cut(reClock); m != nil {
+		h, mins, period := m[1], m[2], m[3]
+		if h == "" {
+			h, mins, period = m[4], m[5], m[6]
+		}
+		hour, _ = strconv.Atoi(h)
+		minute, _ = strconv.Atoi(mins)
+		switch strings.ToLower(period) {
+		case "pm", "sore", "malam":
+			if hour < 12 {
+				hour += 12
+			}
+		case "siang":
+			if hour < 11 {
+				hour += 12
+			}
+		case "am", "pagi":
+			if hour == 12 {
+				hour = 0
+			}
+		}
+		hasClock = hour < 24 && minute < 60
+	}
+
+	switch {
+	case day != nil && hasClock:
+		due := day.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		parsed.DueAt = &due
+	case day != nil:
+		// cuma tanggal → sampai akhir hari itu
+		due := day.Add(23*time.Hour + 59*time.Minute)
+		parsed.DueAt = &due
+	case hasClock:
+		// cuma jam → hari ini, kalau sudah lewat besok
+		due := today.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+		if due.Before(now) {
+			due = due.AddDate(0, 0, 1)
+		}
+		parsed.DueAt = &due
+	}
+
+	parsed.Title = cleanTitle(rest)
+	if parsed.Title == "" {
+		parsed.Title = strings.TrimSpace(text)
+	}
+	return parsed
+}
+
+// cleanTitle rapikan sisa teks: spasi dobel dan tanda baca di ujung dibuang,
+// huruf pertama dibesarkan
+func cleanTitle(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	s = strings.ReplaceAll(s, " ,", ",")
+	s = strings.Trim(s, " ,.;:-")
+	if s == "" {
+		return ""
+	}
+	r, size := utf8.DecodeRuneInString(s)
+	return string(unicode.ToUpper(r)) + s[size:]
+}
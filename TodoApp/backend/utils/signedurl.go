@@ -0,0 +1,29 @@
+package utils
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// SignFile bikin tanda tangan untuk link download /files/:id yang berlaku
+// sampai expires. Link-nya bisa dibuka tanpa header Authorization (misal
+// dari <img> atau dibagikan sebentar), tapi tetap kadaluarsa
+func SignFile(attachmentID uint, expires time.Time) string {
+	mac := hmac.New(sha256.New, jwtkey)
+	fmt.Fprintf(mac, "file:%d:%d", attachmentID, expires.Unix())
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// VerifyFile cek tanda tangan dan waktu kadaluarsa dari query ?expires=&sig=
+func VerifyFile(attachmentID uint, expires, sig string) bool {
+	unix, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > unix {
+		return false
+	}
+	want := SignFile(attachmentID, time.Unix(unix, 0))
+	return hmac.Equal([]byte(want), []byte(sig))
+}
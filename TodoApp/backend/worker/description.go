@@ -0,0 +1,110 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"log"
+	"time"
+	"todo/config"
+	"todo/events"
+	"todo/models"
+	"todo/utils"
+)
+
+const (
+	maxAttempts = 4
+	baseBackoff = 2 * time.Second
+	queueSize   = 1000
+)
+
+type descriptionJob struct {
+	todoID  uint
+	attempt int
+}
+
+var queue chan descriptionJob
+
+// StartDescriptionWorkers jalanin n worker yang bikin deskripsi todo pakai AI.
+// Todo yang masih pending (misal server mati di tengah jalan) dijadwalkan ulang
+func StartDescriptionWorkers(n int) {
+	if n < 1 {
+		n = 1
+	}
+	queue = make(chan descriptionJob, queueSize)
+	for i := 0; i < n; i++ {
+		go func() {
+			for job := range queue {
+				processDescription(job)
+			}
+		}()
+	}
+
+	var ids []uint
+	if err := config.DB.Model(&models.Todo{}).Where("desc_status = ?", models.DescPending).Pluck("id", &ids).Error; err != nil {
+		log.Println("gagal ambil todo pending:", err)
+		return
+	}
+	for _, id := range ids {
+		EnqueueDescription(id)
+	}
+}
+
+// EnqueueDescription jadwalkan pembuatan deskripsi, tidak pernah nge-block
+// handler HTTP walaupun antriannya penuh
+func EnqueueDescription(todoID uint) {
+	enqueue(descriptionJob{todoID: todoID, attempt: 1})
+}
+
+func enqueue(job descriptionJob) {
+	if queue == nil {
+		return
+	}
+	select {
+	case queue <- job:
+	default:
+		go func() { queue <- job }()
+	}
+}
+
+func processDescription(job descriptionJob) {
+	var todo models.Todo
+	if err := config.DB.First(&todo, job.todoID).Error; err != nil {
+		return
+	}
+	// sudah diisi user atau sudah selesai oleh job lain
+	if todo.DescStatus != models.DescPending {
+		return
+	}
+
+	desc, err := utils.GenerateDescription(context.Background(), todo.Title)
+	if err != nil {
+		if job.attempt < maxAttempts && !errors.Is(err, utils.ErrAIDisabled) {
+			// backoff 2s, 4s, 8s, ...
+			delay := baseBackoff << (job.attempt - 1)
+			log.Printf("deskripsi todo %d gagal (percobaan %d), coba lagi dalam %s: %v", todo.ID, job.attempt, delay, err)
+			time.AfterFunc(delay, func() {
+				enqueue(descriptionJob{todoID: job.todoID, attempt: job.attempt + 1})
+			})
+			return
+		}
+		log.Printf("deskripsi todo %d gagal: %v", todo.ID, err)
+		res := config.DB.Model(&models.Todo{}).
+			Where("id = ? AND desc_status = ?", todo.ID, models.DescPending).
+			Updates(map[string]interface{}{"desc_status": models.DescFailed, "desc_error": err.Error()})
+		if res.Error == nil && res.RowsAffected > 0 {
+			todo.DescStatus, todo.DescError = models.DescFailed, err.Error()
+			events.Publish(events.TodoDescription, models.TodoAudience(config.DB, todo), todo.ID, todo)
+		}
+		return
+	}
+
+	// judul bisa saja diganti selama AI jalan, hasil yang basi dibuang
+	// (update judul menjadwalkan job baru)
+	res := config.DB.Model(&models.Todo{}).
+		Where("id = ? AND desc_status = ? AND title = ?", todo.ID, models.DescPending, todo.Title).
+		Updates(map[string]interface{}{"desc": desc, "desc_status": models.DescGenerated, "desc_error": ""})
+	if res.Error == nil && res.RowsAffected > 0 {
+		todo.Desc, todo.DescStatus, todo.DescError = desc, models.DescGenerated, ""
+		events.Publish(events.TodoDescription, models.TodoAudience(config.DB, todo), todo.ID, todo)
+	}
+}
@@ -0,0 +1,58 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+	"todo/events"
+	"todo/models"
+)
+
+// Notification pesan dari scheduler ke user
+type Notification struct {
+	Kind   string     `json:"jenis"` // "reminder" atau "overdue"
+	UserID uint       `json:"user_id"`
+	TodoID uint       `json:"todo_id"`
+	Title  string     `json:"judul"`
+	DueAt  *time.Time `json:"tenggat,omitempty"`
+}
+
+func notification(kind string, todo models.Todo) Notification {
+	return Notification{Kind: kind, UserID: recipient(todo), TodoID: todo.ID, Title: todo.Title, DueAt: todo.DueAt}
+}
+
+// eventType tipe event bus / webhook untuk notifikasi ini
+func (n Notification) eventType() string {
+	if n.Kind == "overdue" {
+		return events.TodoOverdue
+	}
+	return events.TodoReminder
+}
+
+// Notifier tujuan notifikasi scheduler (push, email, dll). Webhook penerima
+// selalu ikut dapat lewat outbox, Notifier jalur tambahannya
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// EventNotifier kirim notifikasi ke event bus, jadi sampai ke client SSE
+// milik penerima sebagai event todo.reminder / todo.overdue. Ini default
+// scheduler
+type EventNotifier struct{}
+
+func (EventNotifier) Notify(ctx context.Context, n Notification) error {
+	events.Publish(n.eventType(), []uint{n.UserID}, n.TodoID, n)
+	return nil
+}
+
+// LogNotifier cuma nulis ke log server, buat debug
+type LogNotifier struct{}
+
+func (LogNotifier) Notify(ctx context.Context, n Notification) error {
+	due := "-"
+	if n.DueAt != nil {
+		due = n.DueAt.Format("2006-01-02 15:04")
+	}
+	log.Printf("[%s] user %d todo %d %q tenggat %s", n.Kind, n.UserID, n.TodoID, n.Title, due)
+	return nil
+}
@@ -0,0 +1,175 @@
+package worker
+
+import (
+	"context"
+	"log"
+	"time"
+	"todo/config"
+	"todo/events"
+	"todo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	reminderBatch = 100
+	// maxBatches batas batch per putaran biar notifier yang terus gagal
+	// tidak bikin loop tanpa akhir
+	maxBatches = 10
+)
+
+// SchedulerConfig pengaturan scheduler pengingat
+type SchedulerConfig struct {
+	Interval time.Duration
+	// FailOverdue pindahkan todo Pending/InProgress yang lewat tenggat ke Failed
+	FailOverdue bool
+	Notifier    Notifier
+}
+
+// StartScheduler cek pengingat dan todo yang lewat tenggat tiap Interval.
+// Aman dijalankan di beberapa instance sekaligus: baris pengingat dikunci
+// pakai FOR UPDATE SKIP LOCKED jadi satu pengingat cuma dikirim satu instance
+func StartScheduler(cfg SchedulerConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = time.Minute
+	}
+	if cfg.Notifier == nil {
+		cfg.Notifier = EventNotifier{}
+	}
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			runScheduler(cfg)
+			<-ticker.C
+		}
+	}()
+}
+
+func runScheduler(cfg SchedulerConfig) {
+	ctx := context.Background()
+	for i := 0; i < maxBatches; i++ {
+		n, err := sendReminders(ctx, cfg.Notifier)
+		if err != nil {
+			log.Println("scheduler: gagal kirim pengingat:", err)
+			break
+		}
+		if n < reminderBatch {
+			break
+		}
+	}
+	if cfg.FailOverdue {
+		if err := failOverdue(ctx, cfg.Notifier); err != nil {
+			log.Println("scheduler: gagal update todo lewat tenggat:", err)
+		}
+	}
+}
+
+// sendReminders kirim satu batch pengingat yang sudah waktunya. Kuncinya
+// dipegang sampai SentAt tersimpan, instance lain melewati baris yang terkunci.
+// Kiriman webhook-nya ditulis ke outbox di transaksi yang sama
+func sendReminders(ctx context.Context, notifier Notifier) (int, error) {
+	count := 0
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		var reminders []models.Reminder
+		active := tx.Model(&models.Todo{}).Select("id").
+			Where("status IN ?", []models.Status{models.Pending, models.InProgress})
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("sent_at IS NULL AND remind_at <= ? AND todo_id IN (?)", time.Now(), active).
+			Order("remind_at").Limit(reminderBatch).
+			Find(&reminders).Error
+		if err != nil {
+			return err
+		}
+		count = len(reminders)
+
+		now := time.Now()
+		for _, r := range reminders {
+			var todo models.Todo
+			if err := tx.First(&todo, r.TodoID).Error; err != nil {
+				continue
+			}
+			n := notification("reminder", todo)
+			if err := notifier.Notify(ctx, n); err != nil {
+				// dicoba lagi di putaran berikutnya
+				log.Printf("scheduler: pengingat %d gagal dikirim: %v", r.ID, err)
+				continue
+			}
+			if err := EnqueueEvent(tx, n.eventType(), []uint{n.UserID}, todo.ID, n); err != nil {
+				return err
+			}
+			if err := tx.Model(&r).Update("sent_at", now).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err == nil && count > 0 {
+		WakeWebhooks()
+	}
+	return count, err
+}
+
+// failOverdue satu UPDATE ... RETURNING, jadi tiap todo cuma dipindah
+// (dan dinotifikasi) sekali walaupun instance-nya banyak. Cuma status yang
+// menurut aturan transisi boleh jadi Failed yang ikut dipindah
+func failOverdue(ctx context.Context, notifier Notifier) error {
+	from := models.StatusesThatCanBecome(models.Failed)
+	if len(from) == 0 {
+		return nil
+	}
+	var failed []models.Todo
+	prevStatus := map[uint]models.Status{}
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		// RETURNING ngasih status baru, status lama diambil lewat history
+		// terakhir atau dianggap Pending
+		if err := tx.Model(&failed).Clauses(clause.Returning{}).
+			Where("due_at < ? AND status IN ?", time.Now(), from).
+			Update("status", models.Failed).Error; err != nil {
+			return err
+		}
+		for _, todo := range failed {
+			prev := models.Pending
+			var last models.StatusHistory
+			if tx.Where("todo_id = ?", todo.ID).Order("created_at desc, id desc").First(&last).Error == nil {
+				prev = last.To
+			}
+			prevStatus[todo.ID] = prev
+			if err := models.RecordStatus(tx, todo.ID, prev, models.Failed, nil, "overdue"); err != nil {
+				return err
+			}
+			audience := models.TodoAudience(tx, todo)
+			if err := EnqueueEvent(tx, events.TodoUpdated, audience, todo.ID, todo); err != nil {
+				return err
+			}
+			change := events.StatusChange{From: prev, To: todo.Status, Todo: todo}
+			if err := EnqueueEvent(tx, events.TodoStatus, audience, todo.ID, change); err != nil {
+				return err
+			}
+			n := notification("overdue", todo)
+			if err := EnqueueEvent(tx, n.eventType(), []uint{n.UserID}, todo.ID, n); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	for _, todo := range failed {
+		audience := models.TodoAudience(config.DB, todo)
+		events.Publish(events.TodoUpdated, audience, todo.ID, todo)
+		events.Publish(events.TodoStatus, audience, todo.ID, events.StatusChange{From: prevStatus[todo.ID], To: todo.Status, Todo: todo})
+		notifier.Notify(ctx, notification("overdue", todo))
+	}
+	return nil
+}
+
+// recipient notifikasi ke assignee, kalau belum ada ke pembuat todo
+func recipient(todo models.Todo) uint {
+	if todo.AssigneeID != nil {
+		return *todo.AssigneeID
+	}
+	return todo.UserID
+}
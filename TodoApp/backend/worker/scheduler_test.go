@@ -0,0 +1,88 @@
+package worker
+
+import (
+	"context"
+	"testing"
+	"time"
+	"todo/config"
+	"todo/events"
+	"todo/models"
+)
+
+func TestSendRemindersNotifiesRecipient(t *testing.T) {
+	useTestDB(t)
+	assignee := uint(2)
+	due := time.Now().Add(30 * time.Minute)
+	todo := models.Todo{Title: "bayar listrik", UserID: 1, AssigneeID: &assignee, DueAt: &due, Status: models.Pending}
+	config.DB.Create(&todo)
+	config.DB.Create(&models.Reminder{TodoID: todo.ID, OffsetMinutes: 60, RemindAt: due.Add(-time.Hour)})
+	hook := models.Webhook{UserID: assignee, URL: "https://example.com/hook", Secret: "whsec_test", Active: true, Events: events.TodoReminder}
+	config.DB.Create(&hook)
+
+	ch, _, _, cancel := events.Default.Subscribe(assignee, 0)
+	defer cancel()
+
+	n, err := sendReminders(context.Background(), EventNotifier{})
+	if err != nil || n != 1 {
+		t.Fatalf("sendReminders = %d, %v", n, err)
+	}
+	select {
+	case e := <-ch:
+		got, ok := e.Data.(Notification)
+		if e.Type != events.TodoReminder || !ok || got.TodoID != todo.ID || got.UserID != assignee {
+			t.Fatalf("event = %+v", e)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("no reminder event on the bus")
+	}
+
+	var deliveries []models.WebhookDelivery
+	config.DB.Where("webhook_id = ?", hook.ID).Find(&deliveries)
+	if len(deliveries) != 1 || deliveries[0].EventType != events.TodoReminder {
+		t.Fatalf("outbox = %+v", deliveries)
+	}
+	var r models.Reminder
+	config.DB.First(&r)
+	if r.SentAt == nil {
+		t.Fatal("reminder should be marked sent")
+	}
+
+	// sudah terkirim, tidak dikirim lagi
+	if n, err := sendReminders(context.Background(), EventNotifier{}); err != nil || n != 0 {
+		t.Fatalf("second run = %d, %v", n, err)
+	}
+}
+
+func TestFailOverdueNotifiesRecipient(t *testing.T) {
+	useTestDB(t)
+	due := time.Now().Add(-time.Hour)
+	todo := models.Todo{Title: "laporan", UserID: 1, DueAt: &due, Status: models.Pending}
+	config.DB.Create(&todo)
+	hook := models.Webhook{UserID: 1, URL: "https://example.com/hook", Secret: "whsec_test", Active: true, Events: events.TodoOverdue}
+	config.DB.Create(&hook)
+
+	ch, _, _, cancel := events.Default.Subscribe(1, 0)
+	defer cancel()
+
+	if err := failOverdue(context.Background(), EventNotifier{}); err != nil {
+		t.Fatal(err)
+	}
+	config.DB.First(&todo, todo.ID)
+	if todo.Status != models.Failed {
+		t.Fatalf("status = %s", todo.Status)
+	}
+	found := false
+	for len(ch) > 0 {
+		if e := <-ch; e.Type == events.TodoOverdue {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatal("no overdue event on the bus")
+	}
+	var count int64
+	config.DB.Model(&models.WebhookDelivery{}).Where("webhook_id = ? AND event_type = ?", hook.ID, events.TodoOverdue).Count(&count)
+	if count != 1 {
+		t.Fatalf("overdue outbox rows = %d", count)
+	}
+}
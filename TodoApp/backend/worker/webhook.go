@@ -0,0 +1,327 @@
+package worker
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+	"todo/config"
+	"todo/events"
+	"todo/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	webhookBatch       = 20
+	webhookBaseBackoff = 30 * time.Second
+	webhookMaxBackoff  = 6 * time.Hour
+	// webhookResponseLog panjang maksimal body balasan yang disimpan di log
+	webhookResponseLog = 1024
+)
+
+// WebhookConfig pengaturan pengiriman webhook
+type WebhookConfig struct {
+	// Interval jarak cek outbox buat kiriman yang dijadwalkan ulang
+	Interval time.Duration
+	// MaxAttempts jatah percobaan per kiriman sebelum dianggap gagal
+	MaxAttempts int
+	// DisableAfter webhook dimatikan setelah sekian kiriman gagal berturut-turut
+	DisableAfter int
+	Timeout      time.Duration
+	// AllowPrivate alamat internal yang tetap boleh dituju, lihat ParseAllowList
+	AllowPrivate []*net.IPNet
+	Client       *http.Client
+}
+
+// WebhookPayload body JSON yang dikirim ke receiver
+type WebhookPayload struct {
+	ID        string      `json:"id"`
+	Type      string      `json:"type"`
+	CreatedAt time.Time   `json:"created_at"`
+	TodoID    uint        `json:"todo_id,omitempty"`
+	Data      interface{} `json:"data"`
+}
+
+var (
+	webhookCfg  WebhookConfig
+	webhookWake = make(chan struct{}, 1)
+)
+
+// StartWebhookWorker mulai ngirim webhook. Kiriman dicatat ke tabel
+// webhook_deliveries (outbox) lewat EnqueueEvent di transaksi perubahannya,
+// worker yang ngirim dan nyoba ulang dengan backoff eksponensial. Kiriman
+// dikunci pakai FOR UPDATE SKIP LOCKED jadi aman untuk banyak instance
+func StartWebhookWorker(cfg WebhookConfig) {
+	if cfg.Interval <= 0 {
+		cfg.Interval = 15 * time.Second
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = 10
+	}
+	if cfg.DisableAfter <= 0 {
+		cfg.DisableAfter = 20
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+	webhookAllow = cfg.AllowPrivate
+	if cfg.Client == nil {
+		cfg.Client = newWebhookClient(cfg.Timeout)
+	}
+	webhookCfg = cfg
+	events.OnPublish(wakeOnEvent)
+
+	go func() {
+		ticker := time.NewTicker(cfg.Interval)
+		defer ticker.Stop()
+		for {
+			runWebhooks(cfg)
+			select {
+			case <-ticker.C:
+			case <-webhookWake:
+			}
+		}
+	}()
+}
+
+// WakeWebhooks bangunin worker biar outbox langsung dicek, panggil setelah
+// transaksi yang nambah kiriman sudah commit
+func WakeWebhooks() {
+	select {
+	case webhookWake <- struct{}{}:
+	default:
+	}
+}
+
+// wakeOnEvent dipanggil bus untuk tiap event. Event dipublish setelah commit,
+// jadi kirimannya sudah ada di outbox dan worker tinggal dibangunin
+func wakeOnEvent(e events.Event) {
+	if models.ValidWebhookEvent(e.Type) {
+		WakeWebhooks()
+	}
+}
+
+// EnqueueEvent bikin satu kiriman per webhook aktif milik userIDs yang
+// melanggan event tersebut. tx harus transaksi yang sama dengan perubahan
+// todo-nya, jadi kiriman tercatat kalau dan hanya kalau perubahannya commit
+func EnqueueEvent(tx *gorm.DB, eventType string, userIDs []uint, todoID uint, data interface{}) error {
+	if !models.ValidWebhookEvent(eventType) || len(userIDs) == 0 {
+		return nil
+	}
+	var hooks []models.Webhook
+	if err := tx.Where("user_id IN ? AND active = ?", userIDs, true).Find(&hooks).Error; err != nil {
+		return err
+	}
+	for _, hook := range hooks {
+		if !hook.Wants(eventType) {
+			continue
+		}
+		if err := EnqueueWebhook(tx, hook, eventType, todoID, data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// EnqueueWebhook simpan satu kiriman ke outbox. Worker tidak dibangunin di
+// sini karena kirimannya belum kelihatan sebelum tx commit
+func EnqueueWebhook(tx *gorm.DB, hook models.Webhook, eventType string, todoID uint, data interface{}) error {
+	payload := WebhookPayload{
+		ID:        "evt_" + models.RandomID(12),
+		Type:      eventType,
+		CreatedAt: time.Now(),
+		TodoID:    todoID,
+		Data:      data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	delivery := models.WebhookDelivery{
+		WebhookID:     hook.ID,
+		EventID:       payload.ID,
+		EventType:     eventType,
+		Payload:       string(body),
+		Status:        models.DeliveryPending,
+		NextAttemptAt: time.Now(),
+	}
+	return tx.Create(&delivery).Error
+}
+
+// Redeliver jadwalkan ulang kiriman yang sudah selesai (gagal atau terkirim)
+// dengan jatah percobaan baru
+func Redeliver(d *models.WebhookDelivery) error {
+	d.Status = models.DeliveryPending
+	d.Attempts = 0
+	d.NextAttemptAt = time.Now()
+	err := config.DB.Model(d).Updates(map[string]any{
+		"status": d.Status, "attempts": 0, "next_attempt_at": d.NextAttemptAt,
+	}).Error
+	if err == nil {
+		WakeWebhooks()
+	}
+	return err
+}
+
+func runWebhooks(cfg WebhookConfig) {
+	for i := 0; i < maxBatches; i++ {
+		deliveries, err := claimDeliveries(cfg)
+		if err != nil {
+			log.Println("webhook: gagal ambil outbox:", err)
+			return
+		}
+		var wg sync.WaitGroup
+		for _, d := range deliveries {
+			wg.Add(1)
+			go func(d models.WebhookDelivery) {
+				defer wg.Done()
+				deliver(cfg, d)
+			}(d)
+		}
+		wg.Wait()
+		if len(deliveries) < webhookBatch {
+			return
+		}
+	}
+}
+
+// claimDeliveries ambil kiriman yang sudah waktunya dan geser
+// next_attempt_at-nya ke depan, jadi instance lain tidak ngambil yang sama
+// selama kiriman ini masih jalan
+func claimDeliveries(cfg WebhookConfig) ([]models.WebhookDelivery, error) {
+	var deliveries []models.WebhookDelivery
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		now := time.Now()
+		active := tx.Model(&models.Webhook{}).Select("id").Where("active = ?", true)
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ? AND webhook_id IN (?)", models.DeliveryPending, now, active).
+			Order("next_attempt_at").Limit(webhookBatch).
+			Find(&deliveries).Error
+		if err != nil || len(deliveries) == 0 {
+			return err
+		}
+		ids := make([]uint, 0, len(deliveries))
+		for _, d := range deliveries {
+			ids = append(ids, d.ID)
+		}
+		lease := now.Add(cfg.Timeout + time.Minute)
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).Update("next_attempt_at", lease).Error
+	})
+	return deliveries, err
+}
+
+// SignWebhook tanda tangan yang dikirim di header X-Webhook-Signature:
+// "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body)). Receiver
+// ngitung ulang dengan X-Webhook-Timestamp dan menolak timestamp yang basi
+func SignWebhook(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	fmt.Fprintf(mac, "%d.", timestamp)
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff jeda sebelum percobaan ke attempt+1: 30s, 1m, 2m, ... maks
+// 6 jam, ditambah jitter biar receiver yang baru pulih tidak diserbu sekaligus
+func webhookBackoff(attempt int) time.Duration {
+	d := webhookBaseBackoff << (attempt - 1)
+	if d <= 0 || d > webhookMaxBackoff {
+		d = webhookMaxBackoff
+	}
+	return d + time.Duration(rand.Int63n(int64(d/10)+1))
+}
+
+func deliver(cfg WebhookConfig, d models.WebhookDelivery) {
+	var hook models.Webhook
+	if err := config.DB.First(&hook, d.WebhookID).Error; err != nil {
+		config.DB.Model(&d).Updates(map[string]any{"status": models.DeliveryFailed, "last_error": "webhook deleted"})
+		return
+	}
+
+	start := time.Now()
+	attempt := models.WebhookAttempt{DeliveryID: d.ID}
+	status, response, err := post(cfg, hook, d)
+	attempt.DurationMs = time.Since(start).Milliseconds()
+	attempt.StatusCode = status
+	attempt.Response = response
+	if err == nil && (status < 200 || status >= 300) {
+		err = fmt.Errorf("receiver returned %d", status)
+	}
+	if err != nil {
+		attempt.Error = err.Error()
+	}
+	config.DB.Create(&attempt)
+
+	d.Attempts++
+	if err == nil {
+		now := time.Now()
+		config.DB.Model(&d).Updates(map[string]any{
+			"status": models.DeliveryDelivered, "attempts": d.Attempts, "delivered_at": now, "last_error": "",
+		})
+		config.DB.Model(&hook).Update("failure_count", 0)
+		return
+	}
+
+	updates := map[string]any{"attempts": d.Attempts, "last_error": attempt.Error}
+	if d.Attempts >= cfg.MaxAttempts {
+		updates["status"] = models.DeliveryFailed
+	} else {
+		updates["next_attempt_at"] = time.Now().Add(webhookBackoff(d.Attempts))
+	}
+	config.DB.Model(&d).Updates(updates)
+	recordFailure(cfg, hook)
+}
+
+// recordFailure naikin hitungan gagal beruntun, kalau sudah lewat batas
+// webhook dimatikan dan kiriman yang tersisa nunggu sampai diaktifkan lagi
+func recordFailure(cfg WebhookConfig, hook models.Webhook) {
+	var updated []models.Webhook
+	err := config.DB.Model(&updated).Clauses(clause.Returning{}).
+		Where("id = ?", hook.ID).
+		Update("failure_count", gorm.Expr("failure_count + 1")).Error
+	if err != nil || len(updated) == 0 || updated[0].FailureCount < cfg.DisableAfter {
+		return
+	}
+	reason := "disabled after " + strconv.Itoa(updated[0].FailureCount) + " consecutive failed deliveries"
+	res := config.DB.Model(&models.Webhook{}).Where("id = ? AND active = ?", hook.ID, true).Updates(map[string]any{
+		"active": false, "disabled_at": time.Now(), "disabled_reason": reason,
+	})
+	if res.RowsAffected > 0 {
+		log.Printf("webhook %d (%s): %s", hook.ID, hook.URL, reason)
+	}
+}
+
+func post(cfg WebhookConfig, hook models.Webhook, d models.WebhookDelivery) (int, string, error) {
+	body := []byte(d.Payload)
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, "", err
+	}
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "TodoApp-Webhook/1")
+	req.Header.Set("X-Webhook-Event", d.EventType)
+	req.Header.Set("X-Webhook-Delivery", d.EventID)
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", SignWebhook(hook.Secret, timestamp, body))
+
+	resp, err := cfg.Client.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	defer resp.Body.Close()
+	snippet, _ := io.ReadAll(io.LimitReader(resp.Body, webhookResponseLog))
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<20))
+	return resp.StatusCode, string(snippet), nil
+}
@@ -0,0 +1,106 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"syscall"
+	"time"
+)
+
+// webhookAllow jaringan internal yang tetap boleh jadi tujuan webhook,
+// diisi dari WEBHOOK_ALLOW_PRIVATE buat receiver lokal waktu development
+var webhookAllow []*net.IPNet
+
+// ParseAllowList ubah daftar IP / CIDR jadi jaringan. IP polos dianggap
+// satu alamat (/32 atau /128)
+func ParseAllowList(list []string) ([]*net.IPNet, error) {
+	var nets []*net.IPNet
+	for _, s := range list {
+		if ip := net.ParseIP(s); ip != nil {
+			bits := 8 * len(ip.To16())
+			if ip.To4() != nil {
+				ip, bits = ip.To4(), 32
+			}
+			nets = append(nets, &net.IPNet{IP: ip, Mask: net.CIDRMask(bits, bits)})
+			continue
+		}
+		_, n, err := net.ParseCIDR(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid IP or CIDR %q", s)
+		}
+		nets = append(nets, n)
+	}
+	return nets, nil
+}
+
+// blockedIP alamat yang tidak boleh dihubungi webhook: loopback, jaringan
+// privat, link-local (termasuk metadata cloud 169.254.169.254), multicast
+// dan 0.0.0.0, kecuali ada di allowlist
+func blockedIP(ip net.IP) bool {
+	for _, n := range webhookAllow {
+		if n.Contains(ip) {
+			return false
+		}
+	}
+	return ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() || ip.IsLinkLocalUnicast() || ip.IsMulticast()
+}
+
+var errWebhookTarget = errors.New("webhook URL points to a private or local address")
+
+// CheckWebhookURL validasi URL webhook waktu dibuat / diubah: harus http(s)
+// dan semua alamat hasil DNS-nya publik. Cek yang sama diulang waktu dial,
+// jadi DNS yang berubah setelah validasi (rebinding) tetap tertahan
+func CheckWebhookURL(u *url.URL) error {
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return errors.New("URL must be an absolute http or https URL")
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	addrs, err := net.DefaultResolver.LookupIPAddr(ctx, u.Hostname())
+	if err != nil {
+		return fmt.Errorf("cannot resolve %s", u.Hostname())
+	}
+	for _, addr := range addrs {
+		if blockedIP(addr.IP) {
+			return errWebhookTarget
+		}
+	}
+	return nil
+}
+
+// dialControl dipanggil tepat sebelum connect, setelah DNS di-resolve
+func dialControl(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+	ip := net.ParseIP(host)
+	if ip == nil || blockedIP(ip) {
+		return fmt.Errorf("%w (%s)", errWebhookTarget, host)
+	}
+	return nil
+}
+
+// newWebhookClient http.Client yang cuma mau connect ke alamat publik, juga
+// waktu ngikutin redirect. Proxy dari env dimatikan karena dial ke proxy
+// bikin alamat tujuan aslinya tidak kecek
+func newWebhookClient(timeout time.Duration) *http.Client {
+	dialer := &net.Dialer{Timeout: timeout, Control: dialControl}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Timeout:   timeout,
+		Transport: transport,
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			if len(via) >= 5 {
+				return errors.New("stopped after 5 redirects")
+			}
+			return CheckWebhookURL(req.URL)
+		},
+	}
+}
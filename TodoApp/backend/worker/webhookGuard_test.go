@@ -0,0 +1,94 @@
+package worker
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+)
+
+func setAllow(t *testing.T, list ...string) {
+	t.Helper()
+	nets, err := ParseAllowList(list)
+	if err != nil {
+		t.Fatal(err)
+	}
+	old := webhookAllow
+	webhookAllow = nets
+	t.Cleanup(func() { webhookAllow = old })
+}
+
+func TestCheckWebhookURL(t *testing.T) {
+	setAllow(t)
+	cases := []struct {
+		url string
+		ok  bool
+	}{
+		{"http://93.184.216.34/hook", true},
+		{"https://[2606:2800:220:1::]/hook", true},
+		{"http://127.0.0.1:8080/hook", false},
+		{"http://[::1]/hook", false},
+		{"http://10.1.2.3/hook", false},
+		{"http://192.168.1.1/hook", false},
+		{"http://169.254.169.254/latest/meta-data", false},
+		{"http://0.0.0.0/hook", false},
+		{"http://[fe80::1]/hook", false},
+		{"ftp://93.184.216.34/hook", false},
+	}
+	for _, c := range cases {
+		u, _ := url.Parse(c.url)
+		if err := CheckWebhookURL(u); (err == nil) != c.ok {
+			t.Errorf("CheckWebhookURL(%s) = %v, want ok=%v", c.url, err, c.ok)
+		}
+	}
+}
+
+func TestCheckWebhookURLAllowList(t *testing.T) {
+	setAllow(t, "127.0.0.1", "10.0.0.0/8")
+	for _, raw := range []string{"http://127.0.0.1:7999/hook", "http://10.9.9.9/hook"} {
+		u, _ := url.Parse(raw)
+		if err := CheckWebhookURL(u); err != nil {
+			t.Errorf("%s should be allowed: %v", raw, err)
+		}
+	}
+	u, _ := url.Parse("http://127.0.0.2/hook")
+	if err := CheckWebhookURL(u); err == nil {
+		t.Error("127.0.0.2 is not in the allow list")
+	}
+	if _, err := ParseAllowList([]string{"not-an-ip"}); err == nil {
+		t.Error("invalid allow list entry should fail")
+	}
+}
+
+func TestWebhookClientRefusesLocalDial(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer srv.Close()
+
+	setAllow(t)
+	_, err := newWebhookClient(2*time.Second).Post(srv.URL, "application/json", nil)
+	if !errors.Is(err, errWebhookTarget) {
+		t.Fatalf("dial to %s: err = %v, want errWebhookTarget", srv.URL, err)
+	}
+
+	setAllow(t, "127.0.0.0/8")
+	resp, err := newWebhookClient(2*time.Second).Post(srv.URL, "application/json", nil)
+	if err != nil {
+		t.Fatalf("allowed dial failed: %v", err)
+	}
+	resp.Body.Close()
+}
+
+func TestWebhookClientChecksRedirect(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, "http://169.254.169.254/latest/meta-data", http.StatusFound)
+	}))
+	defer srv.Close()
+
+	setAllow(t, "127.0.0.0/8")
+	_, err := newWebhookClient(2*time.Second).Post(srv.URL, "application/json", nil)
+	if !errors.Is(err, errWebhookTarget) {
+		t.Fatalf("redirect to metadata: err = %v, want errWebhookTarget", err)
+	}
+}
@@ -0,0 +1,228 @@
+package worker
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+	"todo/config"
+	"todo/migrations"
+	"todo/models"
+
+	"github.com/glebarez/sqlite"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// useTestDB ganti config.DB dengan SQLite baru di folder sementara
+func useTestDB(t *testing.T) {
+	t.Helper()
+	db, err := gorm.Open(sqlite.Open(filepath.Join(t.TempDir(), "test.db")), &gorm.Config{
+		Logger: logger.Default.LogMode(logger.Silent),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := migrations.Up(db, 0); err != nil {
+		t.Fatal(err)
+	}
+	old := config.DB
+	config.DB = db
+	t.Cleanup(func() { config.DB = old })
+}
+
+// receiver server lokal yang nyimpan request terakhir dan balas dengan status
+type receiver struct {
+	*httptest.Server
+	mu     sync.Mutex
+	status int
+	header http.Header
+	body   []byte
+	hits   int
+}
+
+func newReceiver(t *testing.T, status int) *receiver {
+	r := &receiver{status: status}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		r.header, r.body = req.Header.Clone(), body
+		r.hits++
+		w.WriteHeader(r.status)
+		io.WriteString(w, "ok dari receiver")
+	}))
+	t.Cleanup(r.Close)
+	setAllow(t, "127.0.0.0/8")
+	return r
+}
+
+func testConfig() WebhookConfig {
+	return WebhookConfig{MaxAttempts: 2, DisableAfter: 2, Timeout: 2 * time.Second, Client: newWebhookClient(2 * time.Second)}
+}
+
+func createHook(t *testing.T, url string) models.Webhook {
+	t.Helper()
+	hook := models.Webhook{UserID: 1, URL: url, Secret: "whsec_test", Active: true}
+	if err := config.DB.Create(&hook).Error; err != nil {
+		t.Fatal(err)
+	}
+	return hook
+}
+
+func queueDelivery(t *testing.T, hook models.Webhook) models.WebhookDelivery {
+	t.Helper()
+	if err := EnqueueWebhook(config.DB, hook, "todo.created", 42, map[string]string{"judul": "beli susu"}); err != nil {
+		t.Fatal(err)
+	}
+	var d models.WebhookDelivery
+	config.DB.Where("webhook_id = ?", hook.ID).Order("id DESC").First(&d)
+	return d
+}
+
+func reload(t *testing.T, d models.WebhookDelivery) models.WebhookDelivery {
+	t.Helper()
+	var fresh models.WebhookDelivery
+	if err := config.DB.First(&fresh, d.ID).Error; err != nil {
+		t.Fatal(err)
+	}
+	return fresh
+}
+
+func TestSignWebhook(t *testing.T) {
+	body := []byte(`{"id":"evt_1"}`)
+	mac := hmac.New(sha256.New, []byte("whsec_test"))
+	mac.Write([]byte("1700000000." + string(body)))
+	want := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+
+	if got := SignWebhook("whsec_test", 1700000000, body); got != want {
+		t.Fatalf("SignWebhook = %s, want %s", got, want)
+	}
+	if SignWebhook("whsec_lain", 1700000000, body) == want || SignWebhook("whsec_test", 1700000001, body) == want {
+		t.Fatal("signature should depend on secret and timestamp")
+	}
+}
+
+func TestDeliverSuccess(t *testing.T) {
+	useTestDB(t)
+	srv := newReceiver(t, http.StatusOK)
+	hook := createHook(t, srv.URL+"/hook")
+	config.DB.Model(&hook).Update("failure_count", 1)
+	d := queueDelivery(t, hook)
+
+	deliver(testConfig(), d)
+
+	d = reload(t, d)
+	if d.Status != models.DeliveryDelivered || d.Attempts != 1 || d.DeliveredAt == nil {
+		t.Fatalf("delivery = %+v", d)
+	}
+	if string(srv.body) != d.Payload {
+		t.Fatalf("body = %s, want %s", srv.body, d.Payload)
+	}
+	var payload WebhookPayload
+	if err := json.Unmarshal(srv.body, &payload); err != nil || payload.Type != "todo.created" || payload.TodoID != 42 || payload.ID != d.EventID {
+		t.Fatalf("payload = %+v, %v", payload, err)
+	}
+	if srv.header.Get("X-Webhook-Event") != "todo.created" || srv.header.Get("X-Webhook-Delivery") != d.EventID {
+		t.Fatalf("headers = %v", srv.header)
+	}
+	ts, err := strconv.ParseInt(srv.header.Get("X-Webhook-Timestamp"), 10, 64)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := srv.header.Get("X-Webhook-Signature"); got != SignWebhook(hook.Secret, ts, srv.body) {
+		t.Fatalf("signature %s does not match body", got)
+	}
+
+	var attempts []models.WebhookAttempt
+	config.DB.Where("delivery_id = ?", d.ID).Find(&attempts)
+	if len(attempts) != 1 || attempts[0].StatusCode != 200 || attempts[0].Response != "ok dari receiver" || attempts[0].Error != "" {
+		t.Fatalf("attempts = %+v", attempts)
+	}
+	config.DB.First(&hook, hook.ID)
+	if hook.FailureCount != 0 {
+		t.Fatalf("failure count should reset, got %d", hook.FailureCount)
+	}
+}
+
+func TestDeliverRetryThenFail(t *testing.T) {
+	useTestDB(t)
+	srv := newReceiver(t, http.StatusInternalServerError)
+	hook := createHook(t, srv.URL)
+	d := queueDelivery(t, hook)
+	cfg := testConfig()
+
+	before := time.Now()
+	deliver(cfg, d)
+	d = reload(t, d)
+	if d.Status != models.DeliveryPending || d.Attempts != 1 || d.LastError != "receiver returned 500" {
+		t.Fatalf("after first failure: %+v", d)
+	}
+	if d.NextAttemptAt.Before(before.Add(webhookBaseBackoff)) {
+		t.Fatalf("retry scheduled too early: %v", d.NextAttemptAt)
+	}
+
+	deliver(cfg, d)
+	d = reload(t, d)
+	if d.Status != models.DeliveryFailed || d.Attempts != 2 {
+		t.Fatalf("after last attempt: %+v", d)
+	}
+	config.DB.First(&hook, hook.ID)
+	if hook.Active || hook.FailureCount != 2 || hook.DisabledAt == nil {
+		t.Fatalf("hook should be disabled after %d failures: %+v", cfg.DisableAfter, hook)
+	}
+	var n int64
+	config.DB.Model(&models.WebhookAttempt{}).Where("delivery_id = ?", d.ID).Count(&n)
+	if n != 2 {
+		t.Fatalf("attempt log has %d rows", n)
+	}
+}
+
+func TestDeliverBlockedTarget(t *testing.T) {
+	useTestDB(t)
+	srv := newReceiver(t, http.StatusOK)
+	setAllow(t)
+	d := queueDelivery(t, createHook(t, srv.URL))
+
+	deliver(testConfig(), d)
+	d = reload(t, d)
+	if d.Status == models.DeliveryDelivered || srv.hits != 0 {
+		t.Fatalf("delivered to a loopback address: %+v", d)
+	}
+}
+
+func TestRunWebhooksSendsOutbox(t *testing.T) {
+	useTestDB(t)
+	srv := newReceiver(t, http.StatusNoContent)
+	createHook(t, srv.URL)
+	other := createHook(t, srv.URL)
+	config.DB.Model(&other).Update("events", "todo.deleted")
+
+	err := config.DB.Transaction(func(tx *gorm.DB) error {
+		return EnqueueEvent(tx, "todo.updated", []uint{1}, 42, nil)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// transaksi yang gagal tidak ninggalin kiriman
+	config.DB.Transaction(func(tx *gorm.DB) error {
+		EnqueueEvent(tx, "todo.updated", []uint{1}, 43, nil)
+		return gorm.ErrInvalidTransaction
+	})
+
+	runWebhooks(testConfig())
+
+	var deliveries []models.WebhookDelivery
+	config.DB.Find(&deliveries)
+	if len(deliveries) != 1 || deliveries[0].Status != models.DeliveryDelivered || srv.hits != 1 {
+		t.Fatalf("deliveries = %+v, hits = %d", deliveries, srv.hits)
+	}
+}